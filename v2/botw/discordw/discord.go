@@ -11,6 +11,8 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+const loggerName = "discordw"
+
 // discordBot implements the botw.Bot interface.
 type discordBot struct {
 	session *discordgo.Session
@@ -100,7 +102,7 @@ func (d *discordBot) Listen(ctx context.Context, handler botw.Handler) error {
 		// Process the message asynchronously
 		go func() {
 			if err := handler(ctx, stdMsg); err != nil {
-				logw.CtxErrorf(ctx, "discordw: handler failed for message %s: %v", m.ID, err)
+				logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "discordw: handler failed for message %s: %v", m.ID, err)
 			}
 		}()
 	})
@@ -108,10 +110,10 @@ func (d *discordBot) Listen(ctx context.Context, handler botw.Handler) error {
 	if err := d.session.Open(); err != nil {
 		return fmt.Errorf("discordw: failed to open connection: %w", err)
 	}
-	logw.Info("Discord bot connected and listening")
+	logw.Named(loggerName).Info("Discord bot connected and listening")
 
 	<-ctx.Done()
-	logw.Info("Context canceled, stopping Discord bot")
+	logw.Named(loggerName).Info("Context canceled, stopping Discord bot")
 	return d.Close()
 }
 
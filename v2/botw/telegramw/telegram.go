@@ -11,6 +11,8 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+const loggerName = "telegramw"
+
 // telegramBot implements the botw.Bot interface.
 type telegramBot struct {
 	api *tgbotapi.BotAPI
@@ -71,7 +73,7 @@ func (t *telegramBot) Listen(ctx context.Context, handler botw.Handler) error {
 	u.Timeout = 60
 
 	updates := t.api.GetUpdatesChan(u)
-	logw.Info("Telegram bot connected and listening via long-polling")
+	logw.Named(loggerName).Info("Telegram bot connected and listening via long-polling")
 
 	for {
 		select {
@@ -110,7 +112,7 @@ func (t *telegramBot) Listen(ctx context.Context, handler botw.Handler) error {
 						MimeType: mimeType,
 					})
 				} else {
-					logw.Errorf("telegramw: failed to fetch file URL for ID %s: %v", fileID, err)
+					logw.Named(loggerName).Errorf("telegramw: failed to fetch file URL for ID %s: %v", fileID, err)
 				}
 			}
 
@@ -125,12 +127,12 @@ func (t *telegramBot) Listen(ctx context.Context, handler botw.Handler) error {
 
 			go func() {
 				if err := handler(ctx, stdMsg); err != nil {
-					logw.CtxErrorf(ctx, "telegramw: handler failed for message %s: %v", stdMsg.ID, err)
+					logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "telegramw: handler failed for message %s: %v", stdMsg.ID, err)
 				}
 			}()
 
 		case <-ctx.Done():
-			logw.Info("Context canceled, stopping Telegram bot")
+			logw.Named(loggerName).Info("Context canceled, stopping Telegram bot")
 			t.api.StopReceivingUpdates()
 			return nil
 		}
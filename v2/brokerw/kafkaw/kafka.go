@@ -11,6 +11,8 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+const loggerName = "kafkaw"
+
 // kafkaProducer implements brokerw.Producer for Kafka.
 type kafkaProducer struct {
 	writer *kafka.Writer
@@ -85,17 +87,17 @@ func (c *kafkaConsumer) Consume(ctx context.Context, topic string, handlers ...b
 	})
 	c.readers = append(c.readers, r)
 
-	logw.Infof("Kafka Consumer started for topic: %s | Group: %s", topic, c.groupID)
+	logw.Named(loggerName).Infof("Kafka Consumer started for topic: %s | Group: %s", topic, c.groupID)
 
 	go func() {
 		for {
 			m, err := r.FetchMessage(ctx)
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
-					logw.Infof("Kafka Consumer shutting down for topic: %s", topic)
+					logw.Named(loggerName).Infof("Kafka Consumer shutting down for topic: %s", topic)
 					return
 				}
-				logw.Errorf("kafkaw: failed to fetch message: %v", err)
+				logw.Named(loggerName).Errorf("kafkaw: failed to fetch message: %v", err)
 				continue
 			}
 
@@ -107,14 +109,14 @@ func (c *kafkaConsumer) Consume(ctx context.Context, topic string, handlers ...b
 
 			// Execute the middleware chain
 			if err := brokerw.ExecuteHandlers(ctx, stdMsg, handlers...); err != nil {
-				logw.Errorf("kafkaw: handler failed for topic %s: %v", topic, err)
+				logw.Named(loggerName).Errorf("kafkaw: handler failed for topic %s: %v", topic, err)
 				// Note: Skipping CommitMessages causes Kafka to retry this message later
 				continue
 			}
 
 			// Manual Ack: Only commit the offset if all handlers succeeded
 			if err := r.CommitMessages(ctx, m); err != nil {
-				logw.Errorf("kafkaw: failed to commit message offset: %v", err)
+				logw.Named(loggerName).Errorf("kafkaw: failed to commit message offset: %v", err)
 			}
 		}
 	}()
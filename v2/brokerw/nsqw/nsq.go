@@ -9,6 +9,8 @@ import (
 	"github.com/nsqio/go-nsq"
 )
 
+const loggerName = "nsqw"
+
 // nsqProducer implements brokerw.Producer for NSQ.
 type nsqProducer struct {
 	producer *nsq.Producer
@@ -72,7 +74,7 @@ func (c *nsqConsumer) Consume(ctx context.Context, topic string, handlers ...bro
 		return err
 	}
 
-	logw.Infof("NSQ Consumer started for topic: %s | Channel: %s", topic, c.channel)
+	logw.Named(loggerName).Infof("NSQ Consumer started for topic: %s | Channel: %s", topic, c.channel)
 
 	// Register the handler. go-nsq automatically manages concurrency.
 	q.AddHandler(nsq.HandlerFunc(func(m *nsq.Message) error {
@@ -84,7 +86,7 @@ func (c *nsqConsumer) Consume(ctx context.Context, topic string, handlers ...bro
 
 		// Execute the middleware chain
 		if err := brokerw.ExecuteHandlers(ctx, stdMsg, handlers...); err != nil {
-			logw.Errorf("nsqw: handler failed for topic %s: %v", topic, err)
+			logw.Named(loggerName).Errorf("nsqw: handler failed for topic %s: %v", topic, err)
 			return err // Returning an error tells NSQ to Nack and Requeue the message
 		}
 
@@ -101,7 +103,7 @@ func (c *nsqConsumer) Consume(ctx context.Context, topic string, handlers ...bro
 	// Listen for context cancellation to gracefully stop this specific consumer
 	go func() {
 		<-ctx.Done()
-		logw.Infof("Context canceled, stopping NSQ consumer for topic: %s", topic)
+		logw.Named(loggerName).Infof("Context canceled, stopping NSQ consumer for topic: %s", topic)
 		q.Stop()
 	}()
 
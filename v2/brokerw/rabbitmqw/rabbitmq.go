@@ -10,6 +10,8 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const loggerName = "rabbitmqw"
+
 // rabbitProducer implements brokerw.Producer for RabbitMQ.
 type rabbitProducer struct {
 	conn    *amqp.Connection
@@ -109,14 +111,14 @@ func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handlers
 		return fmt.Errorf("rabbitmqw: failed to register consumer: %w", err)
 	}
 
-	logw.Infof("RabbitMQ Consumer listening on queue: %s", queueName)
+	logw.Named(loggerName).Infof("RabbitMQ Consumer listening on queue: %s", queueName)
 
 	go func() {
 		for {
 			select {
 			case d, ok := <-msgs:
 				if !ok {
-					logw.Infof("RabbitMQ Consumer channel closed for queue: %s", queueName)
+					logw.Named(loggerName).Infof("RabbitMQ Consumer channel closed for queue: %s", queueName)
 					return
 				}
 
@@ -128,7 +130,7 @@ func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handlers
 
 				// Execute Middleware Chain
 				if err := brokerw.ExecuteHandlers(ctx, stdMsg, handlers...); err != nil {
-					logw.Errorf("rabbitmqw: handler failed for queue %s: %v", queueName, err)
+					logw.Named(loggerName).Errorf("rabbitmqw: handler failed for queue %s: %v", queueName, err)
 					// Nack and explicitly requeue the message so it can be retried
 					_ = d.Nack(false, true)
 					continue
@@ -138,7 +140,7 @@ func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handlers
 				_ = d.Ack(false)
 
 			case <-ctx.Done():
-				logw.Infof("Context canceled, stopping RabbitMQ consumer for queue: %s", queueName)
+				logw.Named(loggerName).Infof("Context canceled, stopping RabbitMQ consumer for queue: %s", queueName)
 				return
 			}
 		}
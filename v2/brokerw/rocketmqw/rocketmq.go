@@ -14,6 +14,8 @@ import (
 	"github.com/apache/rocketmq-client-go/v2/producer"
 )
 
+const loggerName = "rocketmqw"
+
 // rocketProducer implements brokerw.Producer for RocketMQ.
 type rocketProducer struct {
 	producer rocketmq.Producer
@@ -116,7 +118,7 @@ func (c *rocketConsumer) Consume(ctx context.Context, topic string, handlers ...
 
 			// Execute Middleware Chain
 			if err := brokerw.ExecuteHandlers(cCtx, stdMsg, handlers...); err != nil {
-				logw.Errorf("rocketmqw: handler failed for topic %s: %v", topic, err)
+				logw.Named(loggerName).Errorf("rocketmqw: handler failed for topic %s: %v", topic, err)
 				// Nack: Tells RocketMQ to retry this message later according to its delay levels
 				return consumer.ConsumeRetryLater, err
 			}
@@ -129,7 +131,7 @@ func (c *rocketConsumer) Consume(ctx context.Context, topic string, handlers ...
 		return fmt.Errorf("rocketmqw: failed to subscribe to topic: %w", err)
 	}
 
-	logw.Infof("RocketMQ Consumer started for topic: %s", topic)
+	logw.Named(loggerName).Infof("RocketMQ Consumer started for topic: %s", topic)
 
 	if err := c.consumer.Start(); err != nil {
 		return fmt.Errorf("rocketmqw: failed to start consumer: %w", err)
@@ -138,7 +140,7 @@ func (c *rocketConsumer) Consume(ctx context.Context, topic string, handlers ...
 	// Wait for context cancellation to trigger a shutdown
 	go func() {
 		<-ctx.Done()
-		logw.Infof("Context canceled, shutting down RocketMQ consumer for topic: %s", topic)
+		logw.Named(loggerName).Infof("Context canceled, shutting down RocketMQ consumer for topic: %s", topic)
 		_ = c.Close()
 	}()
 
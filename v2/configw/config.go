@@ -1,63 +1,178 @@
 // Package configw provides a robust configuration loader.
 // It supports loading configurations from YAML files, Dotenv (.env) files,
-// and automatically overrides them with OS Environment Variables.
+// profile and local overlays, OS Environment Variables and command-line flags.
 package configw
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
-
-	"github.com/spf13/viper"
+	"time"
 )
 
+// options holds the optional layers used by Load.
+type options struct {
+	profile      string
+	localFile    string
+	envPrefix    string
+	remote       RemoteSource
+	pollInterval time.Duration
+	args         []string
+	report       *Report
+	secrets      map[string]SecretProvider
+}
+
+// Option applies an optional layer or setting to Load.
+type Option func(*options)
+
+// WithProfile overlays the profile file next to the base file, e.g. "config.staging.yaml"
+// for "config.yaml" and profile "staging". The overlay must exist; an empty profile is ignored.
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithLocalFile overlays a developer-specific file (e.g. "config.local.yaml") on top of the profile.
+// The file is optional and skipped when it does not exist.
+func WithLocalFile(path string) Option {
+	return func(o *options) {
+		o.localFile = path
+	}
+}
+
+// WithEnvPrefix namespaces the environment variables read by Load, e.g. APP_DATABASE_HOST
+// for the key "database.host" and prefix "APP". It also applies to .env files.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = strings.TrimSuffix(strings.ToUpper(prefix), "_")
+	}
+}
+
+// WithRemote merges the values of a remote source (e.g. a key-value store) on top of the files.
+func WithRemote(src RemoteSource) Option {
+	return func(o *options) {
+		o.remote = src
+	}
+}
+
+// WithPollInterval makes Watch poll the remote source set with WithRemote on every interval.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// WithFlags applies command-line flags (usually os.Args[1:]) written as "--db.host=value".
+// Flags take precedence over every other source.
+func WithFlags(args []string) Option {
+	return func(o *options) {
+		o.args = args
+	}
+}
+
+// WithSecretProvider resolves "${scheme:path}" references with p for this load only,
+// taking precedence over providers registered with RegisterSecretProvider.
+func WithSecretProvider(scheme string, p SecretProvider) Option {
+	return func(o *options) {
+		if o.secrets == nil {
+			o.secrets = map[string]SecretProvider{}
+		}
+		o.secrets[scheme] = p
+	}
+}
+
+// WithReport stores which source provided each key into report, for debugging.
+func WithReport(report *Report) Option {
+	return func(o *options) {
+		o.report = report
+	}
+}
+
 // Load reads a configuration file from the given path and unmarshals it into the target struct.
 // The target must be a pointer to a struct holding your configuration fields.
 //
-// Supported formats: .yaml, .yml, .env
-func Load(filePath string, target any) error {
-	v := viper.New()
+// Sources are merged in the following precedence (lowest first):
+//  1. the base file at filePath
+//  2. the profile overlay (WithProfile)
+//  3. the local override file (WithLocalFile)
+//  4. the remote source (WithRemote)
+//  5. OS environment variables, e.g. DB_HOST for the key "db.host" (APP_DB_HOST with WithEnvPrefix("APP"))
+//  6. command-line flags (WithFlags)
+//
+// Keys without a value fall back to their `default:"..."` tag. References such as "${env:X}",
+// "${file:/run/secrets/x}" or "${secret:db/password}" are then resolved through the registered
+// SecretProvider of their scheme; declare such fields as Secret so they are masked when printed or logged.
+// The result is checked with Validate, and every invalid field is returned at once in a *ValidationError.
+//
+// Supported formats: .yaml, .yml, .json, .env. Keys of a .env file may be written as dotted paths
+// (DB.HOST) or as environment variables (DB_HOST), which map onto nested struct fields.
+//
+// Example:
+//
+//	var report configw.Report
+//	err := configw.Load("config/config.yaml", &cfg,
+//		configw.WithProfile(os.Getenv("APP_PROFILE")),
+//		configw.WithLocalFile("config/config.local.yaml"),
+//		configw.WithFlags(os.Args[1:]),
+//		configw.WithReport(&report),
+//	)
+func Load(filePath string, target any, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
 
-	// 1. Set the file path
-	v.SetConfigFile(filePath)
+	if rv := reflect.ValueOf(target); rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("configw: target must be a non-nil pointer, got %T", target)
+	}
+	fields := structFields(reflect.TypeOf(target))
 
-	// 2. Enable automatic OS Environment Variable overrides.
-	// E.g., if you have a struct field `Database.Host`, an OS env var `DATABASE_HOST` will override it.
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	l := newLayers(fields, o.envPrefix)
 
-	// 3. Read the file
-	if err := v.ReadInConfig(); err != nil {
-		// If it's a .env file that wasn't found, you might want to allow it to fail gracefully
-		// if you rely entirely on OS env vars in production. But for strictness, we return the error.
-		return fmt.Errorf("configw: failed to read config file '%s': %w", filePath, err)
+	// 1-3. Files, from the most generic to the most specific.
+	if err := l.readFile(filePath, false); err != nil {
+		return err
 	}
-
-	// 4. Unmarshal into the provided struct
-	// Viper uses the `mapstructure` tag to map fields.
-	if err := v.Unmarshal(target); err != nil {
-		return fmt.Errorf("configw: failed to unmarshal config into struct: %w", err)
+	if o.profile != "" {
+		if err := l.readFile(profilePath(filePath, o.profile), false); err != nil {
+			return err
+		}
+	}
+	if o.localFile != "" {
+		if err := l.readFile(o.localFile, true); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
+	// 4. The remote source overrides every file.
+	if o.remote != nil {
+		if err := l.readRemote(o.remote); err != nil {
+			return err
+		}
+	}
 
-// LoadEnv loads environment variables directly from a .env file into OS environment variables.
-// This is a lightweight alternative if you don't want to unmarshal into a struct.
-func LoadEnv(filePath string) error {
-	v := viper.New()
-	v.SetConfigFile(filePath)
-	v.SetConfigType("env")
+	// 5-6. Environment variables and flags override everything else.
+	l.readEnv()
+	l.readFlags(o.args)
+	l.applyDefaults(fields)
 
-	if err := v.ReadInConfig(); err != nil {
-		return fmt.Errorf("configw: failed to read .env file '%s': %w", filePath, err)
+	secretKeys, err := l.resolveSecrets(o.secrets)
+	if err != nil {
+		return err
 	}
 
-	// Range over the env file and set them to the OS environment so os.Getenv() works.
-	for key, value := range v.AllSettings() {
-		// Viper lowercases keys by default, so we format them back to standard ENV format.
-		// NOTE: viper.BindEnv is typically used, but for raw loading, we set them manually.
-		viper.SetDefault(strings.ToUpper(key), value)
+	// Viper uses the `mapstructure` tag to map fields.
+	if err := l.decode(target); err != nil {
+		return err
+	}
+	warnUnmaskedSecrets(fields, secretKeys)
+	if err := Validate(target); err != nil {
+		return err
 	}
 
+	if o.report != nil {
+		*o.report = l.report
+	}
 	return nil
 }
@@ -1,9 +1,23 @@
 package configw
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+	"github.com/AndreeJait/go-utility/v2/logw/logwtest"
 )
 
 // AppConfig is the target struct we will unmarshal our files into.
@@ -91,3 +105,435 @@ PORT: 8080
 		t.Errorf("Expected PORT to be overridden to 9999, got: %d", cfg.Port)
 	}
 }
+
+func TestLoad_Layers(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "config.yaml")
+	_ = os.WriteFile(base, []byte("APP_NAME: \"BaseApp\"\nPORT: 8080\nDB:\n  HOST: \"localhost\"\n  USER: \"root\"\n"), 0644)
+	_ = os.WriteFile(filepath.Join(tmpDir, "config.staging.yaml"), []byte("DB:\n  HOST: \"staging-db\"\n"), 0644)
+	local := filepath.Join(tmpDir, "config.local.yaml")
+	_ = os.WriteFile(local, []byte("DB:\n  USER: \"dev\"\n"), 0644)
+
+	t.Setenv("APP_NAME", "EnvApp")
+
+	var cfg AppConfig
+	var report Report
+	err := Load(base, &cfg,
+		WithProfile("staging"),
+		WithLocalFile(local),
+		WithFlags([]string{"--port", "7070", "--unrelated=1"}),
+		WithReport(&report),
+	)
+	if err != nil {
+		t.Fatalf("Expected Load to succeed, got: %v", err)
+	}
+
+	if cfg.AppName != "EnvApp" || cfg.Port != 7070 || cfg.DB.Host != "staging-db" || cfg.DB.User != "dev" {
+		t.Errorf("Layered loading failed. Got: %+v", cfg)
+	}
+
+	want := map[string]Source{
+		"app_name": {Kind: SourceEnv, Name: "APP_NAME"},
+		"port":     {Kind: SourceFlag, Name: "--port"},
+		"db.host":  {Kind: SourceFile, Name: filepath.Join(tmpDir, "config.staging.yaml")},
+		"db.user":  {Kind: SourceFile, Name: local},
+	}
+	for key, src := range want {
+		if report[key] != src {
+			t.Errorf("Expected %s to come from %s, got %s", key, src, report[key])
+		}
+	}
+
+	// A missing local file is optional, a missing profile overlay is not.
+	if err := Load(base, &cfg, WithLocalFile(filepath.Join(tmpDir, "missing.yaml"))); err != nil {
+		t.Errorf("Expected a missing local file to be skipped, got: %v", err)
+	}
+	if err := Load(base, &cfg, WithProfile("prod")); err == nil {
+		t.Errorf("Expected a missing profile overlay to fail")
+	}
+}
+
+// ServerConfig exercises defaults, validation and Duration/Size parsing.
+type ServerConfig struct {
+	Port     int           `mapstructure:"port" default:"8080" validate:"min=1,max=65535"`
+	Timeout  time.Duration `mapstructure:"timeout" default:"5s" validate:"min=1s,max=1m"`
+	MaxBody  Size          `mapstructure:"max_body" default:"10MB" validate:"max=100MB"`
+	Mode     string        `mapstructure:"mode" default:"release" validate:"oneof=debug release"`
+	Database struct {
+		DSN  string `mapstructure:"dsn" validate:"required"`
+		Name string `mapstructure:"name" validate:"regex=^[a-z_]+$"`
+	} `mapstructure:"database"`
+}
+
+func TestLoad_FlagValues(t *testing.T) {
+	type flagConfig struct {
+		Port   int    `mapstructure:"port"`
+		Offset int    `mapstructure:"offset"`
+		Debug  bool   `mapstructure:"debug"`
+		Cache  bool   `mapstructure:"cache" default:"true"`
+		Name   string `mapstructure:"name"`
+	}
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(filePath, []byte("port: 80\n"), 0644)
+
+	var cfg flagConfig
+	args := []string{"serve", "--offset", "-1", "--debug", "input.txt", "--cache", "false", "--name=api", "--", "--port", "9090"}
+	if err := Load(filePath, &cfg, WithFlags(args)); err != nil {
+		t.Fatalf("Expected Load to succeed, got: %v", err)
+	}
+	want := flagConfig{Port: 80, Offset: -1, Debug: true, Cache: false, Name: "api"}
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestLoad_DefaultsAndValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	valid := filepath.Join(tmpDir, "valid.yaml")
+	_ = os.WriteFile(valid, []byte("database:\n  dsn: \"postgres://localhost\"\n  name: \"orders\"\n"), 0644)
+
+	var cfg ServerConfig
+	var report Report
+	if err := Load(valid, &cfg, WithReport(&report)); err != nil {
+		t.Fatalf("Expected Load to succeed, got: %v", err)
+	}
+	if cfg.Port != 8080 || cfg.Timeout != 5*time.Second || cfg.MaxBody != 10*Megabyte || cfg.Mode != "release" {
+		t.Errorf("Defaults were not applied. Got: %+v", cfg)
+	}
+	if report["port"].Kind != SourceDefault {
+		t.Errorf("Expected port to come from its default, got %s", report["port"])
+	}
+
+	invalid := filepath.Join(tmpDir, "invalid.yaml")
+	_ = os.WriteFile(invalid, []byte("port: 70000\ntimeout: 2m\nmax_body: 1GB\nmode: verbose\ndatabase:\n  name: \"Orders-DB\"\n"), 0644)
+
+	err := Load(invalid, &ServerConfig{})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected a ValidationError, got: %v", err)
+	}
+	for _, key := range []string{"port", "timeout", "max_body", "mode", "database.dsn", "database.name"} {
+		if !strings.Contains(err.Error(), key+": ") {
+			t.Errorf("Expected the error to report %s, got: %v", key, err)
+		}
+	}
+	if len(verr.Fields) != 6 {
+		t.Errorf("Expected 6 invalid fields, got %d: %v", len(verr.Fields), err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]Size{"512": 512, "10MB": 10 * Megabyte, "1.5 KiB": 1536, "2g": 2 * Gigabyte}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseSize("ten MB"); err == nil {
+		t.Errorf("Expected an invalid size to fail")
+	}
+
+	// Boundaries of int64: 2^63-1 bytes fits, 2^63 bytes (8 EiB) does not.
+	if got, err := ParseSize("9223372036854775807"); err != nil || got != math.MaxInt64 {
+		t.Errorf("Expected the largest size to parse, got %d, %v", got, err)
+	}
+	for _, in := range []string{"9223372036854775808", "8589934592GB", "8589934592.0GB", "9007199254740992TB"} {
+		if got, err := ParseSize(in); err == nil {
+			t.Errorf("Expected ParseSize(%q) to overflow, got %d", in, got)
+		}
+	}
+}
+
+// FeatureConfig is the target of the hot reload tests.
+type FeatureConfig struct {
+	RateLimit int    `mapstructure:"rate_limit" validate:"required,min=1"`
+	Banner    string `mapstructure:"banner"`
+}
+
+// staticRemote is a RemoteSource returning a value that the test can change.
+type staticRemote struct {
+	banner atomic.Value
+}
+
+func (r *staticRemote) Name() string { return "static" }
+
+func (r *staticRemote) Fetch(context.Context) (map[string]any, error) {
+	return map[string]any{"banner": r.banner.Load()}, nil
+}
+
+func TestWatch_Reload(t *testing.T) {
+	rec := logwtest.Override(t)
+
+	filePath := filepath.Join(t.TempDir(), "features.yaml")
+	_ = os.WriteFile(filePath, []byte("rate_limit: 10\n"), 0644)
+
+	remote := &staticRemote{}
+	remote.banner.Store("hello")
+
+	var report Report
+	w, err := Watch[FeatureConfig](filePath, WithRemote(remote), WithPollInterval(20*time.Millisecond), WithReport(&report))
+	if err != nil {
+		t.Fatalf("Expected Watch to succeed, got: %v", err)
+	}
+	defer w.Close(context.Background())
+
+	changes := make(chan [2]FeatureConfig, 10)
+	w.Subscribe(func(old, new *FeatureConfig) { changes <- [2]FeatureConfig{*old, *new} })
+
+	if got := Current[FeatureConfig](); got == nil || got.RateLimit != 10 || got.Banner != "hello" {
+		t.Fatalf("Expected the initial config to be published, got %+v", got)
+	}
+
+	waitChange := func() [2]FeatureConfig {
+		t.Helper()
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for a config change")
+			return [2]FeatureConfig{}
+		}
+	}
+
+	// File change.
+	_ = os.WriteFile(filePath, []byte("rate_limit: 20\n"), 0644)
+	if c := waitChange(); c[0].RateLimit != 10 || c[1].RateLimit != 20 {
+		t.Errorf("Expected rate_limit to change from 10 to 20, got %+v", c)
+	}
+
+	// Remote change picked up by polling.
+	remote.banner.Store("maintenance")
+	if c := waitChange(); c[1].Banner != "maintenance" {
+		t.Errorf("Expected the polled banner, got %+v", c)
+	}
+
+	// Reloads publish their report through the watcher and never write to the caller's Report.
+	if report["banner"].Kind != SourceRemote || w.Report()["rate_limit"].Kind != SourceFile {
+		t.Errorf("Expected the initial and live reports, got %v and %v", report, w.Report())
+	}
+
+	// An invalid reload is rejected and logged; the live config is kept.
+	_ = os.WriteFile(filePath, []byte("rate_limit: 0\n"), 0644)
+	if err := w.Reload(); err == nil {
+		t.Errorf("Expected an invalid reload to be rejected")
+	}
+	if got := Current[FeatureConfig](); got.RateLimit != 20 {
+		t.Errorf("Expected the live config to be kept, got %+v", got)
+	}
+	rec.AssertLogged(t, slog.LevelError, "rejected config reload")
+}
+
+// SecretConfig holds values resolved from secret references.
+type SecretConfig struct {
+	DB struct {
+		User     string `mapstructure:"user"`
+		Password Secret `mapstructure:"password" validate:"required"`
+		Cert     Secret `mapstructure:"cert"`
+	} `mapstructure:"db"`
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	key := bytes.Repeat([]byte{7}, 32)
+	t.Setenv("TEST_SECRETS_KEY", base64.StdEncoding.EncodeToString(key))
+	sealed, err := EncryptSecrets(key, map[string]string{"db/password": "s3cr3t-pass"})
+	if err != nil {
+		t.Fatalf("Failed to encrypt secrets: %v", err)
+	}
+	secretsPath := filepath.Join(tmpDir, "secrets.enc")
+	_ = os.WriteFile(secretsPath, sealed, 0600)
+
+	provider, err := NewEncryptedFileProvider(secretsPath, "TEST_SECRETS_KEY")
+	if err != nil {
+		t.Fatalf("Failed to open encrypted secrets: %v", err)
+	}
+
+	certPath := filepath.Join(tmpDir, "cert")
+	_ = os.WriteFile(certPath, []byte("cert-body\n"), 0600)
+	t.Setenv("TEST_DB_USER", "billing")
+
+	filePath := filepath.Join(tmpDir, "config.yaml")
+	_ = os.WriteFile(filePath, []byte(fmt.Sprintf(
+		"db:\n  user: \"${env:TEST_DB_USER}\"\n  password: \"${secret:db/password}\"\n  cert: \"${file:%s}\"\n", certPath)), 0644)
+
+	var cfg SecretConfig
+	if err := Load(filePath, &cfg, WithSecretProvider("secret", provider)); err != nil {
+		t.Fatalf("Expected Load to succeed, got: %v", err)
+	}
+	if cfg.DB.User != "billing" || cfg.DB.Password.Value() != "s3cr3t-pass" || cfg.DB.Cert.Value() != "cert-body" {
+		t.Errorf("Secret resolution failed. Got user=%s password=%s cert=%s", cfg.DB.User, cfg.DB.Password.Value(), cfg.DB.Cert.Value())
+	}
+
+	var buf bytes.Buffer
+	l, _ := logw.NewLogger(&logw.LogConfig{SendToBroker: true, BrokerWriter: &buf})
+	l.CtxInfoKV(context.Background(), "loaded config", "config", cfg)
+	encoded, _ := json.Marshal(cfg)
+	for _, out := range []string{fmt.Sprintf("%+v", cfg), fmt.Sprintf("%#v", cfg), string(encoded), buf.String()} {
+		if strings.Contains(out, "s3cr3t-pass") || strings.Contains(out, "cert-body") {
+			t.Errorf("Expected secrets to be masked, got: %s", out)
+		}
+	}
+
+	// Unknown schemes are reported with the key path.
+	_ = os.WriteFile(filePath, []byte("db:\n  password: \"${vault:db/password}\"\n"), 0644)
+	if err := Load(filePath, &SecretConfig{}); err == nil || !strings.Contains(err.Error(), "db.password") {
+		t.Errorf("Expected an unknown provider to fail for db.password, got: %v", err)
+	}
+
+	// Failing rules on a secret never echo its value.
+	type ruledSecrets struct {
+		Short   Secret `mapstructure:"short" validate:"min=32"`
+		Long    Secret `mapstructure:"long" validate:"max=4"`
+		Mode    Secret `mapstructure:"mode" validate:"oneof=a b"`
+		Pattern Secret `mapstructure:"pattern" validate:"regex=^[0-9]+$"`
+	}
+	_ = os.WriteFile(filePath, []byte("short: hunter2-supersecret\nlong: hunter2-supersecret\nmode: hunter2-supersecret\npattern: hunter2-supersecret\n"), 0644)
+	err = Load(filePath, &ruledSecrets{})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 4 {
+		t.Fatalf("Expected 4 invalid secrets, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "got ******") {
+		t.Errorf("Expected secrets to be masked in validation errors, got: %v", err)
+	}
+	_ = os.WriteFile(filePath, []byte("short: 0123456789abcdef0123456789abcdef\nlong: abc\nmode: b\npattern: \"42\"\n"), 0644)
+	if err := Load(filePath, &ruledSecrets{}); err != nil {
+		t.Errorf("Expected rules to check the unmasked secret, got: %v", err)
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("TEST_DOTENV_HOST", "db.internal")
+	t.Setenv("TEST_DOTENV_KEEP", "from-os")
+	for _, k := range []string{"TEST_DOTENV_URL", "TEST_DOTENV_RAW", "TEST_DOTENV_ESCAPED", "TEST_DOTENV_CERT", "TEST_DOTENV_PORT"} {
+		t.Setenv(k, "")
+		os.Unsetenv(k)
+	}
+
+	filePath := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(filePath, []byte(`# database
+export TEST_DOTENV_PORT=5432 # inline comment
+TEST_DOTENV_URL="postgres://${TEST_DOTENV_HOST}:${TEST_DOTENV_PORT}/${TEST_DOTENV_DB:-app}"
+TEST_DOTENV_RAW='${TEST_DOTENV_HOST}'
+TEST_DOTENV_ESCAPED="price \${TEST_DOTENV_HOST} \\${TEST_DOTENV_HOST}"
+TEST_DOTENV_CERT="-----BEGIN-----
+line\tone
+-----END-----"
+TEST_DOTENV_KEEP=from-file
+`), 0644)
+
+	if err := LoadEnv(filePath, WithoutOverride()); err != nil {
+		t.Fatalf("Expected LoadEnv to succeed, got: %v", err)
+	}
+
+	want := map[string]string{
+		"TEST_DOTENV_PORT":    "5432",
+		"TEST_DOTENV_URL":     "postgres://db.internal:5432/app",
+		"TEST_DOTENV_RAW":     "${TEST_DOTENV_HOST}",
+		"TEST_DOTENV_ESCAPED": `price ${TEST_DOTENV_HOST} \db.internal`,
+		"TEST_DOTENV_CERT":    "-----BEGIN-----\nline\tone\n-----END-----",
+		"TEST_DOTENV_KEEP":    "from-os",
+	}
+	for k, v := range want {
+		if got := os.Getenv(k); got != v {
+			t.Errorf("Expected %s=%q, got %q", k, v, got)
+		}
+	}
+
+	if err := LoadEnv(filePath); err != nil || os.Getenv("TEST_DOTENV_KEEP") != "from-file" {
+		t.Errorf("Expected LoadEnv to override by default, got %q (err %v)", os.Getenv("TEST_DOTENV_KEEP"), err)
+	}
+
+	_ = os.WriteFile(filePath, []byte("TEST_DOTENV_URL=\"unterminated\n"), 0644)
+	if err := LoadEnv(filePath); err == nil {
+		t.Error("Expected an unterminated quote to fail")
+	}
+}
+
+func TestLoad_EnvPrefix(t *testing.T) {
+	type NestedConfig struct {
+		Database struct {
+			Host string `mapstructure:"host"`
+			Port int    `mapstructure:"port"`
+		} `mapstructure:"database"`
+	}
+
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "app.env")
+	_ = os.WriteFile(filePath, []byte("APP_DATABASE_HOST=from-file\nAPP_DATABASE_PORT=5432\n"), 0644)
+	t.Setenv("APP_DATABASE_HOST", "from-env")
+	t.Setenv("DATABASE_PORT", "1")
+
+	var report Report
+	var cfg NestedConfig
+	if err := Load(filePath, &cfg, WithEnvPrefix("APP"), WithReport(&report)); err != nil {
+		t.Fatalf("Expected Load to succeed, got: %v", err)
+	}
+	if cfg.Database.Host != "from-env" || cfg.Database.Port != 5432 {
+		t.Errorf("Expected prefixed env and .env keys to map onto nested fields, got: %+v", cfg)
+	}
+	if got := report["database.host"]; got != (Source{Kind: SourceEnv, Name: "APP_DATABASE_HOST"}) {
+		t.Errorf("Expected database.host from APP_DATABASE_HOST, got %s", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	type DocConfig struct {
+		Server struct {
+			Port    int           `mapstructure:"port" default:"8080" validate:"min=1,max=65535" description:"HTTP listen port"`
+			Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+		} `mapstructure:"server"`
+		Database struct {
+			DSN Secret `mapstructure:"dsn" validate:"required" description:"Postgres connection string"`
+		} `mapstructure:"database"`
+		Level string `mapstructure:"level" default:"info" validate:"oneof=debug info"`
+	}
+
+	d := Describe(&DocConfig{}, WithEnvPrefix("app"))
+	if len(d.Keys) != 4 {
+		t.Fatalf("Expected 4 keys, got %+v", d.Keys)
+	}
+	dsn := d.Keys[2]
+	if dsn.Key != "database.dsn" || dsn.Env != "APP_DATABASE_DSN" || !dsn.Required || !dsn.Secret || dsn.Type != "string" {
+		t.Errorf("Unexpected key info for database.dsn: %+v", dsn)
+	}
+	if d.Keys[1].Type != "duration" || d.Keys[1].Default != "5s" {
+		t.Errorf("Unexpected key info for server.timeout: %+v", d.Keys[1])
+	}
+
+	if md := d.Markdown(); !strings.Contains(md, "| `server.port` | `APP_SERVER_PORT` | int | `8080` |  | HTTP listen port |") {
+		t.Errorf("Unexpected Markdown:\n%s", md)
+	}
+	if env := d.SampleEnv(); !strings.Contains(env, "# HTTP listen port (int)\nAPP_SERVER_PORT=8080\n") ||
+		!strings.Contains(env, "APP_DATABASE_DSN=\n") {
+		t.Errorf("Unexpected sample .env:\n%s", env)
+	}
+	wantYAML := "server:\n  # HTTP listen port (int)\n  port: 8080\n  # (duration)\n  timeout: \"5s\"\n" +
+		"database:\n  # Postgres connection string (string, required, secret)\n  dsn: \"\"\n# (string)\nlevel: \"info\"\n"
+	if got := d.SampleYAML(); got != wantYAML {
+		t.Errorf("Unexpected sample YAML:\n%s", got)
+	}
+
+	raw, err := d.JSONSchema()
+	if err != nil {
+		t.Fatalf("Expected JSONSchema to succeed, got: %v", err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	port := schema.Properties["server"].Properties["port"]
+	if port["type"] != "integer" || port["minimum"] != 1.0 || port["maximum"] != 65535.0 || port["default"] != 8080.0 {
+		t.Errorf("Unexpected schema for server.port: %v", port)
+	}
+	if req := schema.Properties["database"].Required; len(req) != 1 || req[0] != "dsn" {
+		t.Errorf("Expected database.dsn to be required, got %v", req)
+	}
+}
@@ -0,0 +1,405 @@
+package configw
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const descriptionTagName = "description"
+
+// KeyInfo documents a single configuration key.
+type KeyInfo struct {
+	Key         string // Key is the lowercase dotted path, e.g. "database.host".
+	Env         string // Env is the environment variable that overrides the key, e.g. "APP_DATABASE_HOST".
+	Type        string // Type is a short type name: string, int, uint, float, bool, duration, size, time, []T or map.
+	Default     string // Default is the `default:"..."` tag, if any.
+	Required    bool   // Required is set by the "required" validation rule.
+	Secret      bool   // Secret is set for fields of type Secret.
+	Description string // Description is the `description:"..."` tag.
+	Validate    string // Validate holds the raw `validate:"..."` rules.
+
+	kind reflect.Type
+}
+
+// Description lists every key accepted by a configuration struct, in field order.
+type Description struct {
+	Keys []KeyInfo
+}
+
+// Describe documents the configuration struct target (a struct or a pointer to one), as Load would read it.
+// Pass the same WithEnvPrefix option used with Load so the environment variable names match.
+//
+// Example:
+//
+//	type Config struct {
+//		Port int    `mapstructure:"port" default:"8080" description:"HTTP listen port"`
+//		DSN  Secret `mapstructure:"dsn" validate:"required" description:"Postgres connection string"`
+//	}
+//
+//	d := configw.Describe(Config{}, configw.WithEnvPrefix("APP"))
+//	os.WriteFile("docs/config.md", []byte(d.Markdown()), 0644)
+//	os.WriteFile(".env.example", []byte(d.SampleEnv()), 0644)
+func Describe(target any, opts ...Option) *Description {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	d := &Description{}
+	if target == nil {
+		return d
+	}
+	for _, f := range structFields(reflect.TypeOf(target)) {
+		rules := f.sf.Tag.Get(validateTagName)
+		def, _ := f.sf.Tag.Lookup(defaultTagName)
+		d.Keys = append(d.Keys, KeyInfo{
+			Key:         f.key,
+			Env:         envName(o.envPrefix, f.key),
+			Type:        typeName(f.sf.Type),
+			Default:     def,
+			Required:    hasRule(rules, "required"),
+			Secret:      indirectType(f.sf.Type) == secretType,
+			Description: f.sf.Tag.Get(descriptionTagName),
+			Validate:    rules,
+			kind:        indirectType(f.sf.Type),
+		})
+	}
+	return d
+}
+
+// Markdown renders the keys as a Markdown table.
+func (d *Description) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("| Key | Env | Type | Default | Required | Description |\n")
+	sb.WriteString("|-----|-----|------|---------|----------|-------------|\n")
+	for _, k := range d.Keys {
+		def := ""
+		if k.Default != "" {
+			def = "`" + k.Default + "`"
+		}
+		required := ""
+		if k.Required {
+			required = "yes"
+		}
+		typ := k.Type
+		if k.Secret {
+			typ += " (secret)"
+		}
+		fmt.Fprintf(&sb, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			k.Key, k.Env, typ, markdownEscape(def), required, markdownEscape(k.Description))
+	}
+	return sb.String()
+}
+
+// SampleEnv renders a sample .env file with every key set to its default.
+// Secrets are left empty so the sample can be committed.
+func (d *Description) SampleEnv() string {
+	var sb strings.Builder
+	for i, k := range d.Keys {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		if comment := keyComment(k); comment != "" {
+			sb.WriteString("# " + comment + "\n")
+		}
+		value := k.Default
+		if k.Secret {
+			value = ""
+		}
+		if strings.ContainsAny(value, " #\"'$\n") {
+			value = strconv.Quote(value)
+		}
+		sb.WriteString(k.Env + "=" + value + "\n")
+	}
+	return sb.String()
+}
+
+// SampleYAML renders a sample YAML file with every key set to its default, or to an empty value.
+func (d *Description) SampleYAML() string {
+	root := &yamlNode{}
+	for i := range d.Keys {
+		root.insert(strings.Split(d.Keys[i].Key, "."), &d.Keys[i])
+	}
+
+	var sb strings.Builder
+	root.write(&sb, 0)
+	return sb.String()
+}
+
+// JSONSchema renders a JSON Schema (draft 2020-12) that configuration files can be validated against.
+// Keys with a default are not listed as required, since Load fills them in.
+func (d *Description) JSONSchema() ([]byte, error) {
+	root := jsonObject()
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+	for _, k := range d.Keys {
+		parts := strings.Split(k.Key, ".")
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			props := parent["properties"].(map[string]any)
+			child, ok := props[part].(map[string]any)
+			if !ok {
+				child = jsonObject()
+				props[part] = child
+			}
+			parent = child
+		}
+
+		name := parts[len(parts)-1]
+		parent["properties"].(map[string]any)[name] = k.jsonProperty()
+		if k.Required && k.Default == "" {
+			parent["required"] = append(parent["required"].([]string), name)
+		}
+	}
+
+	pruneRequired(root)
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func jsonObject() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}, "required": []string{}}
+}
+
+func pruneRequired(obj map[string]any) {
+	if req, ok := obj["required"].([]string); ok && len(req) == 0 {
+		delete(obj, "required")
+	}
+	for _, p := range obj["properties"].(map[string]any) {
+		if child, ok := p.(map[string]any); ok && child["properties"] != nil {
+			pruneRequired(child)
+		}
+	}
+}
+
+// jsonProperty converts the key into a JSON Schema property, mapping min/max, oneof and regex rules.
+func (k KeyInfo) jsonProperty() map[string]any {
+	prop := map[string]any{}
+	if k.Description != "" {
+		prop["description"] = k.Description
+	}
+
+	t := k.kind
+	if t == nil {
+		t = reflect.TypeOf("")
+	}
+	switch {
+	case t == durationType:
+		prop["type"] = "string"
+	case t == sizeType:
+		prop["type"] = []string{"string", "integer"}
+	case t == timeType:
+		prop["type"] = "string"
+		prop["format"] = "date-time"
+	case t.Kind() == reflect.Bool:
+		prop["type"] = "boolean"
+	case isIntKind(t.Kind()):
+		prop["type"] = "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		prop["type"] = "number"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		prop["type"] = "array"
+		prop["items"] = KeyInfo{kind: indirectType(t.Elem())}.jsonProperty()
+	case t.Kind() == reflect.Map:
+		prop["type"] = "object"
+	default:
+		prop["type"] = "string"
+	}
+
+	if k.Default != "" {
+		prop["default"] = jsonDefault(prop["type"], k.Default)
+	}
+
+	rules, regex, hasRegex := strings.Cut(k.Validate, "regex=")
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "oneof" {
+			var enum []any
+			for _, opt := range strings.Fields(arg) {
+				enum = append(enum, jsonDefault(prop["type"], opt))
+			}
+			prop["enum"] = enum
+			continue
+		}
+		if (name != "min" && name != "max") || t == durationType || t == sizeType || t == timeType {
+			continue
+		}
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			continue
+		}
+		switch prop["type"] {
+		case "integer", "number":
+			prop[name+"imum"] = bound // minimum, maximum
+		case "string":
+			prop[name+"Length"] = int(bound)
+		case "array":
+			prop[name+"Items"] = int(bound)
+		}
+	}
+	if hasRegex && prop["type"] == "string" {
+		prop["pattern"] = regex
+	}
+	return prop
+}
+
+func jsonDefault(typ any, def string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(def, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(def); err == nil {
+			return b
+		}
+	case "array":
+		return strings.Split(def, ",")
+	}
+	return def
+}
+
+// yamlNode is a node of the nested key tree rendered by SampleYAML.
+type yamlNode struct {
+	name     string
+	info     *KeyInfo
+	children []*yamlNode
+}
+
+func (n *yamlNode) insert(parts []string, info *KeyInfo) {
+	for _, c := range n.children {
+		if c.name == parts[0] && c.info == nil && len(parts) > 1 {
+			c.insert(parts[1:], info)
+			return
+		}
+	}
+	child := &yamlNode{name: parts[0]}
+	n.children = append(n.children, child)
+	if len(parts) == 1 {
+		child.info = info
+		return
+	}
+	child.insert(parts[1:], info)
+}
+
+func (n *yamlNode) write(sb *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, c := range n.children {
+		if c.info == nil {
+			sb.WriteString(indent + c.name + ":\n")
+			c.write(sb, depth+1)
+			continue
+		}
+		if comment := keyComment(*c.info); comment != "" {
+			sb.WriteString(indent + "# " + comment + "\n")
+		}
+		sb.WriteString(indent + c.name + ": " + yamlValue(*c.info) + "\n")
+	}
+}
+
+func yamlValue(k KeyInfo) string {
+	t := k.kind
+	if t == nil {
+		t = reflect.TypeOf("")
+	}
+	if k.Default == "" || k.Secret {
+		switch {
+		case t.Kind() == reflect.Bool:
+			return "false"
+		case isIntKind(t.Kind()) && t != durationType && t != sizeType, t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+			return "0"
+		case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+			return "[]"
+		case t.Kind() == reflect.Map:
+			return "{}"
+		default:
+			return `""`
+		}
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		items := strings.Split(k.Default, ",")
+		for i, item := range items {
+			items[i] = strconv.Quote(strings.TrimSpace(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case t.Kind() == reflect.Bool, isIntKind(t.Kind()) && t != durationType && t != sizeType,
+		t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return k.Default
+	default:
+		return strconv.Quote(k.Default)
+	}
+}
+
+// keyComment summarizes a key for the sample files, e.g. "HTTP listen port (int, required)".
+func keyComment(k KeyInfo) string {
+	attrs := []string{k.Type}
+	if k.Required {
+		attrs = append(attrs, "required")
+	}
+	if k.Secret {
+		attrs = append(attrs, "secret")
+	}
+	comment := "(" + strings.Join(attrs, ", ") + ")"
+	if k.Description != "" {
+		comment = k.Description + " " + comment
+	}
+	return comment
+}
+
+// typeName returns the short type name shown in the documentation.
+func typeName(t reflect.Type) string {
+	t = indirectType(t)
+	switch {
+	case t == durationType:
+		return "duration"
+	case t == sizeType:
+		return "size"
+	case t == timeType:
+		return "time"
+	case t == secretType:
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice, reflect.Array:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map"
+	default:
+		return "string"
+	}
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || (k >= reflect.Uint && k <= reflect.Uint64)
+}
+
+// hasRule reports whether the comma separated validation rules contain name.
+func hasRule(tag, name string) bool {
+	rules, _, _ := strings.Cut(tag, "regex=")
+	for _, rule := range strings.Split(rules, ",") {
+		if r, _, _ := strings.Cut(strings.TrimSpace(rule), "="); r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
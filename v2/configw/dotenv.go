@@ -0,0 +1,237 @@
+package configw
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// envVarRef matches "${VAR}" and "${VAR:-fallback}" inside .env values.
+// Secret references such as "${env:X}" use a single colon and are left for the secret resolver.
+var envVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)(:-[^}]*)?\}`)
+
+// EnvVar is a single assignment read from a .env file.
+type EnvVar struct {
+	Key   string
+	Value string
+}
+
+// ParseDotenv parses the content of a .env file, keeping the order of the assignments. It supports:
+//
+//	# comments and blank lines
+//	export KEY=value            the "export" prefix is ignored
+//	KEY=value # comment         unquoted values are trimmed and end at " #"
+//	KEY="line1\nline2 ${HOST}"  double quotes allow escapes, interpolation and literal line breaks
+//	KEY='raw ${NOT_EXPANDED}'   single quotes are taken literally and may also span lines
+//
+// "${VAR}" and "${VAR:-fallback}" are resolved from earlier assignments in the file, then from the OS environment.
+// In double quotes, "\${VAR}" keeps the reference literal.
+func ParseDotenv(content string) ([]EnvVar, error) {
+	var vars []EnvVar
+	values := map[string]string{}
+
+	lookup := func(name string) (string, bool) {
+		if v, ok := values[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lineNo := 0
+	for len(content) > 0 {
+		var line string
+		line, content, _ = strings.Cut(content, "\n")
+		lineNo++
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		trimmed = strings.TrimPrefix(trimmed, "export ")
+
+		key, rest, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("configw: invalid .env line %d: %q", lineNo, line)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, `'`):
+			quote := rest[0]
+			raw, remaining, lines, err := readQuoted(rest[1:], content, quote)
+			if err != nil {
+				return nil, fmt.Errorf("configw: invalid .env line %d: %w", lineNo, err)
+			}
+			content = remaining
+			lineNo += lines
+			value = raw
+			if quote == '"' {
+				value = expandQuoted(raw, lookup)
+			}
+		default:
+			if i := strings.Index(rest, " #"); i >= 0 {
+				rest = rest[:i]
+			}
+			value = interpolate(strings.TrimSpace(rest), lookup)
+		}
+
+		values[key] = value
+		vars = append(vars, EnvVar{Key: key, Value: value})
+	}
+	return vars, nil
+}
+
+// readQuoted reads a quoted value that may continue on the following lines.
+// It returns the raw value, the unread content and the number of extra lines consumed.
+func readQuoted(first, content string, quote byte) (string, string, int, error) {
+	var sb strings.Builder
+	current := first
+	lines := 0
+	for {
+		for i := 0; i < len(current); i++ {
+			c := current[i]
+			if c == '\\' && quote == '"' && i+1 < len(current) {
+				sb.WriteByte(c)
+				sb.WriteByte(current[i+1])
+				i++
+				continue
+			}
+			if c == quote {
+				return sb.String(), content, lines, nil
+			}
+			sb.WriteByte(c)
+		}
+		if content == "" {
+			return "", "", lines, fmt.Errorf("unterminated %c quote", quote)
+		}
+		sb.WriteByte('\n')
+		current, content, _ = strings.Cut(content, "\n")
+		lines++
+	}
+}
+
+// dotenvEscapes maps the character after a backslash in a double-quoted value to its replacement.
+var dotenvEscapes = map[byte]string{'n': "\n", 'r': "\r", 't': "\t", '"': `"`, '\\': `\`, '$': "$"}
+
+// expandQuoted resolves the escapes and references of a double-quoted value in a single pass,
+// so an escaped "\${VAR}" stays literal and expanded values are not unescaped again.
+func expandQuoted(s string, lookup func(string) (string, bool)) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			if r, ok := dotenvEscapes[s[i+1]]; ok {
+				sb.WriteString(r)
+			} else {
+				sb.WriteString(s[i : i+2])
+			}
+			i++
+		case s[i] == '$':
+			if loc := envVarRef.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				sb.WriteString(interpolate(s[i:i+loc[1]], lookup))
+				i += loc[1] - 1
+				continue
+			}
+			sb.WriteByte('$')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+func interpolate(s string, lookup func(string) (string, bool)) string {
+	return envVarRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envVarRef.FindStringSubmatch(ref)
+		if v, ok := lookup(m[1]); ok && v != "" {
+			return v
+		}
+		return strings.TrimPrefix(m[2], ":-")
+	})
+}
+
+// isDotenvFile reports whether path is a .env file (".env", "app.env", ".dev.env").
+func isDotenvFile(path string) bool {
+	return filepath.Ext(path) == ".env" || filepath.Base(path) == ".env"
+}
+
+// readDotenv merges a .env file. Keys written as environment variables (e.g. APP_DATABASE_HOST)
+// are mapped onto the matching nested struct key; dotted keys (e.g. DB.HOST) are used as is.
+func (l *layers) readDotenv(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("configw: failed to read .env file '%s': %w", path, err)
+	}
+	vars, err := ParseDotenv(string(data))
+	if err != nil {
+		return fmt.Errorf("configw: failed to parse .env file '%s': %w", path, err)
+	}
+
+	byEnv := map[string]string{}
+	for _, f := range l.fields {
+		if _, taken := byEnv[l.envName(f.key)]; !taken {
+			byEnv[l.envName(f.key)] = f.key
+		}
+	}
+
+	src := Source{Kind: SourceFile, Name: path}
+	for _, v := range vars {
+		key, ok := byEnv[strings.ToUpper(v.Key)]
+		if !ok {
+			key = strings.ToLower(v.Key)
+		}
+		l.set(key, v.Value, src)
+	}
+	return nil
+}
+
+// envOptions holds the settings of LoadEnv.
+type envOptions struct {
+	noOverride bool
+}
+
+// EnvOption applies a setting to LoadEnv.
+type EnvOption func(*envOptions)
+
+// WithoutOverride keeps variables that are already set in the OS environment.
+func WithoutOverride() EnvOption {
+	return func(o *envOptions) {
+		o.noOverride = true
+	}
+}
+
+// LoadEnv loads environment variables directly from a .env file into OS environment variables,
+// so os.Getenv sees them. See ParseDotenv for the supported syntax.
+// This is a lightweight alternative if you don't want to unmarshal into a struct.
+func LoadEnv(filePath string, opts ...EnvOption) error {
+	o := &envOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("configw: failed to read .env file '%s': %w", filePath, err)
+	}
+	vars, err := ParseDotenv(string(data))
+	if err != nil {
+		return err
+	}
+
+	for _, v := range vars {
+		if o.noOverride {
+			if _, exists := os.LookupEnv(v.Key); exists {
+				continue
+			}
+		}
+		if err := os.Setenv(v.Key, v.Value); err != nil {
+			return fmt.Errorf("configw: failed to set %s: %w", v.Key, err)
+		}
+	}
+	return nil
+}
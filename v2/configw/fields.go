@@ -0,0 +1,99 @@
+package configw
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const keyTagName = "mapstructure"
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// field describes a leaf configuration value of the target struct.
+type field struct {
+	key   string // key is the lowercase dotted path, e.g. "database.host".
+	index []int  // index is the reflect field path from the root struct.
+	sf    reflect.StructField
+}
+
+// structFields lists the leaf fields of the struct type t, keyed the same way viper decodes them:
+// by the `mapstructure` tag (or the field name), joined with dots and lowercased.
+// Embedded structs tagged `mapstructure:",squash"` share the parent's prefix.
+func structFields(t reflect.Type) []field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return appendFields(nil, t, "", nil)
+}
+
+func appendFields(out []field, t reflect.Type, prefix string, index []int) []field {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get(keyTagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		path := append(append([]int(nil), index...), i)
+		ft := indirectType(sf.Type)
+
+		if strings.Contains(opts, "squash") && ft.Kind() == reflect.Struct {
+			out = appendFields(out, ft, prefix, path)
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		key := strings.ToLower(prefix + name)
+
+		if isLeafType(ft) {
+			out = append(out, field{key: key, index: path, sf: sf})
+			continue
+		}
+		out = appendFields(out, ft, key+".", path)
+	}
+	return out
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isLeafType reports whether values of t are decoded as a whole rather than field by field.
+func isLeafType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// fieldValue returns the value of f inside root, or false if a nil pointer is on the way.
+func fieldValue(root reflect.Value, f field) (reflect.Value, bool) {
+	v := root
+	for _, i := range f.index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
@@ -0,0 +1,258 @@
+package configw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// SourceKind identifies the kind of layer that provided a configuration value.
+type SourceKind string
+
+const (
+	SourceDefault SourceKind = "default" // SourceDefault is the `default:"..."` struct tag.
+	SourceFile    SourceKind = "file"    // SourceFile is the base file, a profile overlay or the local override.
+	SourceRemote  SourceKind = "remote"  // SourceRemote is a RemoteSource.
+	SourceEnv     SourceKind = "env"     // SourceEnv is an OS environment variable.
+	SourceFlag    SourceKind = "flag"    // SourceFlag is a command-line flag.
+)
+
+// Source describes where a configuration value came from, e.g. file "config.staging.yaml" or env "DB_HOST".
+type Source struct {
+	Kind SourceKind
+	Name string
+}
+
+func (s Source) String() string {
+	return string(s.Kind) + ":" + s.Name
+}
+
+// Report maps every configuration key (lowercase dotted path, e.g. "db.host") to the source that provided it.
+type Report map[string]Source
+
+// String renders the report as sorted "key = source" lines for debugging.
+func (r Report) String() string {
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s = %s\n", k, r[k])
+	}
+	return sb.String()
+}
+
+// layers is the flattened result of merging every source; later layers overwrite earlier ones.
+type layers struct {
+	values    map[string]any
+	report    Report
+	fields    []field
+	envPrefix string
+}
+
+func newLayers(fields []field, envPrefix string) *layers {
+	return &layers{values: map[string]any{}, report: Report{}, fields: fields, envPrefix: envPrefix}
+}
+
+func (l *layers) set(key string, value any, src Source) {
+	key = strings.ToLower(key)
+	l.values[key] = value
+	l.report[key] = src
+}
+
+// knownKeys returns the keys declared by the target struct together with every key read so far.
+func (l *layers) knownKeys() []string {
+	seen := make(map[string]bool, len(l.fields)+len(l.values))
+	var keys []string
+	for _, f := range l.fields {
+		if !seen[f.key] {
+			seen[f.key] = true
+			keys = append(keys, f.key)
+		}
+	}
+	for k := range l.values {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readFile merges a configuration file. A missing file is an error unless optional is set.
+func (l *layers) readFile(path string, optional bool) error {
+	if optional {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+	if isDotenvFile(path) {
+		return l.readDotenv(path)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("configw: failed to read config file '%s': %w", path, err)
+	}
+
+	src := Source{Kind: SourceFile, Name: path}
+	for _, key := range v.AllKeys() {
+		l.set(key, v.Get(key), src)
+	}
+	return nil
+}
+
+// RemoteSource provides configuration values from outside the file system, such as Consul, etcd
+// or an HTTP endpoint. Fetch returns nested maps keyed like the configuration files.
+type RemoteSource interface {
+	Name() string
+	Fetch(ctx context.Context) (map[string]any, error)
+}
+
+// remoteFetchTimeout bounds a single RemoteSource.Fetch call.
+const remoteFetchTimeout = 10 * time.Second
+
+// readRemote merges the values fetched from src.
+func (l *layers) readRemote(src RemoteSource) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFetchTimeout)
+	defer cancel()
+
+	values, err := src.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("configw: failed to fetch remote config from %s: %w", src.Name(), err)
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(values); err != nil {
+		return fmt.Errorf("configw: invalid remote config from %s: %w", src.Name(), err)
+	}
+	source := Source{Kind: SourceRemote, Name: src.Name()}
+	for _, key := range v.AllKeys() {
+		l.set(key, v.Get(key), source)
+	}
+	return nil
+}
+
+// readEnv overrides known keys with OS environment variables, e.g. "db.host" with DB_HOST.
+// Empty variables are ignored.
+func (l *layers) readEnv() {
+	for _, key := range l.knownKeys() {
+		name := l.envName(key)
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			l.set(key, value, Source{Kind: SourceEnv, Name: name})
+		}
+	}
+}
+
+// envName converts a configuration key into its environment variable name, e.g. APP_DB_HOST
+// for "db.host" with the prefix "APP".
+func (l *layers) envName(key string) string {
+	return envName(l.envPrefix, key)
+}
+
+func envName(prefix, key string) string {
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return name
+}
+
+// readFlags overrides known keys with command-line flags written as "--db.host=value" or "--db.host value".
+// A flag without a value (e.g. "--debug") is read as "true"; boolean keys only take a following "true" or
+// "false", so "--debug input.txt" leaves the positional argument alone. Negative numbers ("--offset -1")
+// are values, not flags. Parsing stops at "--". Flags for unknown keys are ignored,
+// so args may also carry flags meant for other parsers.
+func (l *layers) readFlags(args []string) {
+	known := make(map[string]bool)
+	for _, key := range l.knownKeys() {
+		known[key] = true
+	}
+	boolKeys := make(map[string]bool)
+	for _, f := range l.fields {
+		t := f.sf.Type
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		boolKeys[f.key] = t.Kind() == reflect.Bool
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || isNumber(arg) {
+			continue // positional argument
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		key := strings.ToLower(name)
+		if !hasValue {
+			value = "true"
+			if i+1 < len(args) && isFlagValue(args[i+1], boolKeys[key]) {
+				value = args[i+1]
+				i++
+			}
+		}
+
+		if known[key] {
+			l.set(key, value, Source{Kind: SourceFlag, Name: "--" + name})
+		}
+	}
+}
+
+// isFlagValue reports whether next is the value of the preceding flag rather than another flag
+// or a positional argument.
+func isFlagValue(next string, boolean bool) bool {
+	if boolean {
+		_, err := strconv.ParseBool(next)
+		return err == nil
+	}
+	return next == "-" || !strings.HasPrefix(next, "-") || isNumber(next)
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// profilePath returns the overlay of base for the given profile, e.g. "config.staging.yaml" for "config.yaml".
+func profilePath(base, profile string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + profile + ext
+}
+
+// decode unmarshals the merged values into target through viper, so `mapstructure` tags,
+// weak typing (e.g. "8080" into an int) and duration strings behave as in a single-file load.
+// Types implementing encoding.TextUnmarshaler (such as Size) are decoded from strings.
+func (l *layers) decode(target any) error {
+	v := viper.New()
+	for key, value := range l.values {
+		v.Set(key, value)
+	}
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.TextUnmarshallerHookFunc(),
+	))
+	if err := v.Unmarshal(target, hook); err != nil {
+		return fmt.Errorf("configw: failed to unmarshal config into struct: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package configw
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+const (
+	secretMask           = "******"
+	secretResolveTimeout = 10 * time.Second
+)
+
+// secretRef matches "${scheme:path}" references, e.g. "${env:DB_PASSWORD}" or "${secret:db/password}".
+var secretRef = regexp.MustCompile(`\$\{([A-Za-z][A-Za-z0-9_-]*):([^}]*)\}`)
+
+// Secret is a configuration string that is masked whenever it is printed, marshaled or logged.
+// Use it for fields filled from secret references; Value returns the real content.
+type Secret string
+
+// Value returns the unmasked secret.
+func (s Secret) Value() string { return string(s) }
+
+func (s Secret) String() string   { return secretMask }
+func (s Secret) GoString() string { return secretMask }
+
+// Format masks the secret for every fmt verb, including %#v and %q.
+func (s Secret) Format(f fmt.State, _ rune) { _, _ = io.WriteString(f, secretMask) }
+
+func (s Secret) MarshalJSON() ([]byte, error) { return json.Marshal(secretMask) }
+func (s Secret) MarshalText() ([]byte, error) { return []byte(secretMask), nil }
+
+// LogValue masks the secret in logw/slog output.
+func (s Secret) LogValue() slog.Value { return slog.StringValue(secretMask) }
+
+// SecretProvider resolves the path of a "${scheme:path}" reference for the scheme it is registered under.
+type SecretProvider interface {
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, path string) (string, error)
+
+func (f SecretProviderFunc) Resolve(ctx context.Context, path string) (string, error) {
+	return f(ctx, path)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]SecretProvider{
+		"env":  SecretProviderFunc(resolveEnv),
+		"file": SecretProviderFunc(resolveFile),
+	}
+)
+
+// RegisterSecretProvider makes p resolve "${scheme:path}" references in every Load.
+// The built-in schemes are "env" (an environment variable) and "file" (the content of a file,
+// e.g. a Docker or Kubernetes secret mounted under /run/secrets).
+//
+// Example:
+//
+//	p, err := configw.NewEncryptedFileProvider("/etc/app/secrets.enc", "APP_SECRETS_KEY")
+//	configw.RegisterSecretProvider("secret", p) // resolves ${secret:db/password}
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[scheme] = p
+}
+
+func lookupProvider(scheme string, local map[string]SecretProvider) (SecretProvider, bool) {
+	if p, ok := local[scheme]; ok {
+		return p, true
+	}
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[scheme]
+	return p, ok
+}
+
+func resolveEnv(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func resolveFile(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveSecrets expands every "${scheme:path}" reference in the merged values. It returns the keys
+// whose value came from a provider other than "env", so they can be checked for masking.
+func (l *layers) resolveSecrets(local map[string]SecretProvider) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	var secretKeys []string
+	var errs []FieldError
+	for key, value := range l.values {
+		resolved, secret, err := expandRefs(ctx, value, local)
+		if err != nil {
+			errs = append(errs, FieldError{Key: key, Message: err.Error()})
+			continue
+		}
+		l.values[key] = resolved
+		if secret {
+			secretKeys = append(secretKeys, key)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Fields: errs}
+	}
+	return secretKeys, nil
+}
+
+// expandRefs resolves references in strings, including those nested in lists.
+func expandRefs(ctx context.Context, value any, local map[string]SecretProvider) (any, bool, error) {
+	switch v := value.(type) {
+	case string:
+		return expandString(ctx, v, local)
+	case []any:
+		out := make([]any, len(v))
+		secret := false
+		for i, item := range v {
+			resolved, s, err := expandRefs(ctx, item, local)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i], secret = resolved, secret || s
+		}
+		return out, secret, nil
+	default:
+		return value, false, nil
+	}
+}
+
+func expandString(ctx context.Context, s string, local map[string]SecretProvider) (string, bool, error) {
+	var firstErr error
+	secret := false
+	out := secretRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := secretRef.FindStringSubmatch(ref)
+		scheme, path := m[1], m[2]
+
+		p, ok := lookupProvider(scheme, local)
+		if !ok {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("no secret provider registered for %q", scheme)
+			}
+			return ref
+		}
+		value, err := p.Resolve(ctx, path)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to resolve ${%s:%s}: %w", scheme, path, err)
+			}
+			return ref
+		}
+		if scheme != "env" {
+			secret = true
+		}
+		return value
+	})
+	return out, secret, firstErr
+}
+
+// warnUnmaskedSecrets logs keys resolved from a secret provider into a field that is not a Secret,
+// because such values would be printed in clear text.
+func warnUnmaskedSecrets(fields []field, secretKeys []string) {
+	for _, key := range secretKeys {
+		for _, f := range fields {
+			if f.key == key && indirectType(f.sf.Type) != secretType {
+				logw.Named(loggerName).Warningf("%s holds a resolved secret but is not a configw.Secret, so it is not masked", key)
+			}
+		}
+	}
+}
+
+// EncryptedFileProvider resolves paths from a local file holding a JSON object of secrets
+// (e.g. {"db/password": "..."}) encrypted with AES-GCM. The file is read once at construction.
+type EncryptedFileProvider struct {
+	secrets map[string]string
+}
+
+// NewEncryptedFileProvider decrypts the file at path with the base64 key stored in the keyEnv
+// environment variable. The key must decode to 16, 24 or 32 bytes (AES-128/192/256).
+func NewEncryptedFileProvider(path, keyEnv string) (*EncryptedFileProvider, error) {
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(keyEnv))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("configw: %s must hold a base64 encoded AES key", keyEnv)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("configw: failed to read secrets file '%s': %w", path, err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("configw: secrets file '%s' is too short", path)
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("configw: failed to decrypt secrets file '%s': %w", path, err)
+	}
+
+	p := &EncryptedFileProvider{}
+	if err := json.Unmarshal(plain, &p.secrets); err != nil {
+		return nil, fmt.Errorf("configw: secrets file '%s' must hold a JSON object of strings: %w", path, err)
+	}
+	return p, nil
+}
+
+// Resolve returns the secret stored under path.
+func (p *EncryptedFileProvider) Resolve(_ context.Context, path string) (string, error) {
+	value, ok := p.secrets[path]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", path)
+	}
+	return value, nil
+}
+
+// EncryptSecrets produces the content of a file readable by NewEncryptedFileProvider:
+// a random nonce followed by the AES-GCM sealed JSON object.
+func EncryptSecrets(key []byte, secrets map[string]string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("configw: invalid AES key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
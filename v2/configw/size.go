@@ -0,0 +1,90 @@
+package configw
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Size is a byte count that can be written in configuration as a number of bytes
+// or with a unit, e.g. "512KB", "10MB" or "1.5GiB". Units are powers of 1024.
+type Size int64
+
+const (
+	Byte     Size = 1
+	Kilobyte      = 1024 * Byte
+	Megabyte      = 1024 * Kilobyte
+	Gigabyte      = 1024 * Megabyte
+	Terabyte      = 1024 * Gigabyte
+)
+
+var sizeUnits = map[string]Size{
+	"": Byte, "b": Byte,
+	"k": Kilobyte, "kb": Kilobyte, "kib": Kilobyte,
+	"m": Megabyte, "mb": Megabyte, "mib": Megabyte,
+	"g": Gigabyte, "gb": Gigabyte, "gib": Gigabyte,
+	"t": Terabyte, "tb": Terabyte, "tib": Terabyte,
+}
+
+// ParseSize parses a human-readable size such as "10MB" into a Size.
+func ParseSize(s string) (Size, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("configw: invalid size %q", s)
+	}
+
+	// Whole numbers are checked exactly; float64 cannot represent every int64.
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(multiplier) {
+			return 0, fmt.Errorf("configw: size %q overflows", s)
+		}
+		return Size(n) * multiplier, nil
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("configw: invalid size %q", s)
+	}
+	bytes := n * float64(multiplier)
+	// float64(math.MaxInt64) rounds up to 2^63, which no longer fits in an int64.
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("configw: size %q overflows", s)
+	}
+	return Size(bytes), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler so sizes decode from strings.
+func (s *Size) UnmarshalText(text []byte) error {
+	parsed, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// String renders the size with the largest unit that divides it exactly, e.g. "10MB".
+func (s Size) String() string {
+	for _, u := range []struct {
+		size Size
+		name string
+	}{{Terabyte, "TB"}, {Gigabyte, "GB"}, {Megabyte, "MB"}, {Kilobyte, "KB"}} {
+		if s != 0 && s%u.size == 0 {
+			return strconv.FormatInt(int64(s/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// Bytes returns the size as a plain byte count.
+func (s Size) Bytes() int64 {
+	return int64(s)
+}
@@ -0,0 +1,237 @@
+package configw
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTagName  = "default"
+	validateTagName = "validate"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	sizeType     = reflect.TypeOf(Size(0))
+	secretType   = reflect.TypeOf(Secret(""))
+)
+
+// FieldError describes a single invalid configuration value.
+type FieldError struct {
+	Key     string // Key is the full key path, e.g. "database.dsn".
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationError aggregates every invalid field found by Validate.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("configw: invalid configuration:")
+	for _, f := range e.Fields {
+		sb.WriteString("\n  - ")
+		sb.WriteString(f.Error())
+	}
+	return sb.String()
+}
+
+// applyDefaults fills every key without a value from its `default:"..."` tag.
+func (l *layers) applyDefaults(fields []field) {
+	for _, f := range fields {
+		def, ok := f.sf.Tag.Lookup(defaultTagName)
+		if !ok {
+			continue
+		}
+		if _, set := l.values[f.key]; !set {
+			l.set(f.key, def, Source{Kind: SourceDefault, Name: def})
+		}
+	}
+}
+
+// Validate checks target against its `validate` tags and returns a *ValidationError listing
+// every invalid field. Rules are comma separated:
+//
+//	required        the value must not be the zero value
+//	min=N, max=N    bounds for numbers, durations ("5s") and sizes ("10MB"); length for strings, slices and maps
+//	oneof=a b c     the value must be one of the space separated options
+//	regex=EXPR      the string must match EXPR; it must be the last rule because EXPR may contain commas
+//
+// Rules other than required are skipped for zero values, so optional fields may stay empty.
+//
+// Example:
+//
+//	type Config struct {
+//		Port    int           `mapstructure:"port" default:"8080" validate:"min=1,max=65535"`
+//		DSN     string        `mapstructure:"dsn" validate:"required"`
+//		Timeout time.Duration `mapstructure:"timeout" default:"5s" validate:"min=1s,max=1m"`
+//		Level   string        `mapstructure:"level" default:"info" validate:"oneof=debug info warn error"`
+//	}
+func Validate(target any) error {
+	rv := reflect.ValueOf(target)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("configw: cannot validate a nil %T", target)
+		}
+		rv = rv.Elem()
+	}
+
+	var errs []FieldError
+	for _, f := range structFields(rv.Type()) {
+		tag := f.sf.Tag.Get(validateTagName)
+		if tag == "" {
+			continue
+		}
+
+		v, ok := fieldValue(rv, f)
+		if !ok {
+			// A nil parent struct leaves the field unset.
+			v = reflect.Zero(f.sf.Type)
+		}
+		for _, msg := range validateField(v, tag) {
+			errs = append(errs, FieldError{Key: f.key, Message: msg})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// validateField returns a message for every rule of tag that v violates.
+func validateField(v reflect.Value, tag string) []string {
+	rules, regex, hasRegex := strings.Cut(tag, "regex=")
+	var msgs []string
+
+	zero := !v.IsValid() || v.IsZero()
+	for v.IsValid() && v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(rule, "=")
+
+		if name == "required" {
+			if zero {
+				msgs = append(msgs, "is required")
+			}
+			continue
+		}
+		if zero {
+			continue
+		}
+
+		switch name {
+		case "min", "max":
+			if msg := checkBound(v, name, arg); msg != "" {
+				msgs = append(msgs, msg)
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			got := rawValue(v)
+			found := false
+			for _, opt := range options {
+				if got == opt {
+					found = true
+					break
+				}
+			}
+			if !found {
+				msgs = append(msgs, fmt.Sprintf("must be one of [%s], got %s", strings.Join(options, " "), formatValue(v)))
+			}
+		default:
+			msgs = append(msgs, fmt.Sprintf("unknown validation rule %q", name))
+		}
+	}
+
+	if hasRegex && !zero {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid regex rule %q: %v", regex, err))
+		} else if !re.MatchString(rawValue(v)) {
+			msgs = append(msgs, fmt.Sprintf("must match %s, got %s", regex, formatValue(v)))
+		}
+	}
+	return msgs
+}
+
+// checkBound compares v against a min/max rule argument parsed according to v's type.
+func checkBound(v reflect.Value, rule, arg string) string {
+	var got, bound float64
+	var err error
+	unit := ""
+
+	switch {
+	case v.Type() == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(arg)
+		got, bound = float64(v.Int()), float64(d)
+	case v.Type() == sizeType:
+		var s Size
+		s, err = ParseSize(arg)
+		got, bound = float64(v.Int()), float64(s)
+	case v.CanInt():
+		got = float64(v.Int())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanUint():
+		got = float64(v.Uint())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanFloat():
+		got = v.Float()
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.Kind() == reflect.String || v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		got = float64(v.Len())
+		bound, err = strconv.ParseFloat(arg, 64)
+		unit = " in length"
+	default:
+		return fmt.Sprintf("rule %s is not supported for %s", rule, v.Type())
+	}
+	if err != nil {
+		return fmt.Sprintf("invalid %s rule %q", rule, arg)
+	}
+
+	if rule == "min" && got < bound {
+		return fmt.Sprintf("must be at least %s%s, got %s", arg, unit, formatValue(v))
+	}
+	if rule == "max" && got > bound {
+		return fmt.Sprintf("must be at most %s%s, got %s", arg, unit, formatValue(v))
+	}
+	return ""
+}
+
+// rawValue returns the string that oneof and regex rules check. Unlike fmt, it does not mask secrets.
+func rawValue(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatValue formats v for a validation message, masking secrets.
+func formatValue(v reflect.Value) string {
+	if v.Type() == secretType {
+		return secretMask
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return strconv.Itoa(v.Len()) + " items"
+	case reflect.String:
+		return strconv.Quote(v.String())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,240 @@
+package configw
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+	"github.com/fsnotify/fsnotify"
+)
+
+const loggerName = "configw"
+
+// reloadDebounce coalesces the burst of events editors and deploy tools emit for a single save.
+const reloadDebounce = 100 * time.Millisecond
+
+// currents holds the live configuration of every watched type, keyed by reflect.Type.
+// Each value is an *atomic.Pointer[T] for the matching T.
+var currents sync.Map
+
+// Current returns the live configuration of type T published by Watch, or nil if T is not watched.
+// The returned value must be treated as read-only; reloads swap in a fresh struct instead of mutating it.
+//
+// Example:
+//
+//	if configw.Current[AppConfig]().Features.NewCheckout { ... }
+func Current[T any]() *T {
+	if p, ok := currents.Load(reflect.TypeFor[T]()); ok {
+		return p.(*atomic.Pointer[T]).Load()
+	}
+	return nil
+}
+
+// Watcher reloads a configuration whenever its files change (or its remote source is polled)
+// and publishes every valid result through Current.
+type Watcher[T any] struct {
+	filePath string
+	opts     []Option
+	current  *atomic.Pointer[T]
+	report   atomic.Pointer[Report] // sources of the live configuration, replaced on every reload
+	reloadMu sync.Mutex             // serializes reloads so subscribers see consistent old/new pairs
+
+	subMu       sync.Mutex
+	subscribers []func(old, new *T)
+
+	fsw       *fsnotify.Watcher
+	files     map[string]bool
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Watch loads the configuration like Load and keeps it up to date. Every reload is unmarshaled into a fresh
+// struct and validated; invalid reloads are logged and rejected without touching the live configuration.
+// With WithRemote and WithPollInterval, the remote source is also polled for changes.
+// Call Close (e.g. via gracefulw.Register) to stop watching. WithReport only receives the report of the
+// initial load; use Watcher.Report for later reloads.
+//
+// Example:
+//
+//	w, err := configw.Watch[AppConfig]("config/config.yaml", configw.WithProfile("staging"))
+//	w.Subscribe(func(old, new *AppConfig) {
+//		limiter.SetLimit(new.RateLimit)
+//	})
+//	gracefulw.Register("ConfigWatcher", w.Close)
+func Watch[T any](filePath string, opts ...Option) (*Watcher[T], error) {
+	initial := new(T)
+	report, err := loadWithReport(filePath, initial, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.report != nil {
+		*o.report = report
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("configw: failed to create file watcher: %w", err)
+	}
+
+	w := &Watcher[T]{
+		filePath: filePath,
+		opts:     opts,
+		fsw:      fsw,
+		files:    map[string]bool{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	// Directories are watched instead of files so atomic replaces (rename over the file) are seen.
+	paths := []string{filePath}
+	if o.profile != "" {
+		paths = append(paths, profilePath(filePath, o.profile))
+	}
+	if o.localFile != "" {
+		paths = append(paths, o.localFile)
+	}
+	dirs := map[string]bool{}
+	for _, p := range paths {
+		w.files[filepath.Clean(p)] = true
+		dirs[filepath.Dir(p)] = true
+	}
+	for dir := range dirs {
+		if err := fsw.Add(dir); err != nil {
+			_ = fsw.Close()
+			return nil, fmt.Errorf("configw: failed to watch %s: %w", dir, err)
+		}
+	}
+
+	p, _ := currents.LoadOrStore(reflect.TypeFor[T](), &atomic.Pointer[T]{})
+	w.current = p.(*atomic.Pointer[T])
+	w.current.Store(initial)
+	w.report.Store(&report)
+
+	var pollInterval time.Duration
+	if o.remote != nil {
+		pollInterval = o.pollInterval
+	}
+	go w.loop(pollInterval)
+	return w, nil
+}
+
+// Current returns the live configuration held by this Watcher.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with the previous and the new configuration after every
+// successful reload that changed a value. Subscribers run sequentially on the watcher goroutine.
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) {
+	w.subMu.Lock()
+	defer w.subMu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Report returns which source provided each key of the live configuration.
+// The returned Report must be treated as read-only.
+func (w *Watcher[T]) Report() Report {
+	return *w.report.Load()
+}
+
+// Reload re-reads every source immediately. It returns the error that caused a reload to be rejected.
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	next := new(T)
+	report, err := loadWithReport(w.filePath, next, w.opts)
+	if err != nil {
+		logw.Named(loggerName).Errorf("rejected config reload of %s: %v", w.filePath, err)
+		return err
+	}
+
+	old := w.current.Load()
+	if reflect.DeepEqual(old, next) {
+		return nil
+	}
+	w.current.Store(next)
+	w.report.Store(&report)
+	logw.Named(loggerName).Infof("reloaded config from %s", w.filePath)
+
+	w.subMu.Lock()
+	subscribers := slices.Clone(w.subscribers)
+	w.subMu.Unlock()
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+	return nil
+}
+
+// loadWithReport is Load with a fresh Report, so reloads never write to the caller's WithReport target.
+func loadWithReport(filePath string, target any, opts []Option) (Report, error) {
+	var report Report
+	err := Load(filePath, target, append(opts[:len(opts):len(opts)], WithReport(&report))...)
+	return report, err
+}
+
+func (w *Watcher[T]) loop(pollInterval time.Duration) {
+	defer close(w.done)
+
+	var poll <-chan time.Time
+	if pollInterval > 0 {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if w.files[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				debounce.Reset(reloadDebounce)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			logw.Named(loggerName).Errorf("config watcher error: %v", err)
+		case <-debounce.C:
+			_ = w.Reload()
+		case <-poll:
+			_ = w.Reload()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Close stops watching. The last valid configuration stays available through Current.
+// Its signature matches gracefulw.CleanupFunc.
+func (w *Watcher[T]) Close(ctx context.Context) error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		err = w.fsw.Close()
+	})
+	select {
+	case <-w.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
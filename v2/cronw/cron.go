@@ -10,6 +10,8 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+const loggerName = "cronw"
+
 // Handler defines the signature for processing a scheduled job.
 // If it returns an error, the error will be logged by the scheduler.
 type Handler func(ctx context.Context) error
@@ -62,12 +64,12 @@ func (s *cronScheduler) Register(pattern string, handler Handler, middlewares ..
 		// Create a fresh context for this specific job execution
 		ctx := context.Background()
 
-		logw.Infof("cronw: starting job [pattern: %s]", pattern)
+		logw.Named(loggerName).Infof("cronw: starting job [pattern: %s]", pattern)
 
 		if err := ExecuteHandlers(ctx, allHandlers...); err != nil {
-			logw.Errorf("cronw: job failed [pattern: %s]: %v", pattern, err)
+			logw.Named(loggerName).Errorf("cronw: job failed [pattern: %s]: %v", pattern, err)
 		} else {
-			logw.Infof("cronw: job completed successfully [pattern: %s]", pattern)
+			logw.Named(loggerName).Infof("cronw: job completed successfully [pattern: %s]", pattern)
 		}
 	}
 
@@ -82,17 +84,17 @@ func (s *cronScheduler) Register(pattern string, handler Handler, middlewares ..
 
 func (s *cronScheduler) Start() {
 	s.cronEngine.Start()
-	logw.Info("cronw: scheduler started")
+	logw.Named(loggerName).Info("cronw: scheduler started")
 }
 
 func (s *cronScheduler) Close() error {
-	logw.Info("cronw: shutting down scheduler, waiting for active jobs to complete...")
+	logw.Named(loggerName).Info("cronw: shutting down scheduler, waiting for active jobs to complete...")
 
 	// Stop() prevents new jobs from starting and returns a context.
 	// The context's Done() channel is closed when all running jobs finish.
 	ctx := s.cronEngine.Stop()
 	<-ctx.Done()
 
-	logw.Info("cronw: scheduler successfully stopped")
+	logw.Named(loggerName).Info("cronw: scheduler successfully stopped")
 	return nil
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/AndreeJait/go-utility/v2/logw"
 )
 
+const loggerName = "emailw"
+
 // Attachment represents a file to be sent along with the email.
 type Attachment struct {
 	Filename string
@@ -133,10 +135,10 @@ func (s *smtpEmailer) Send(ctx context.Context, msg Message) error {
 	fullMsg := []byte(header + content)
 	err := smtp.SendMail(addr, auth, s.from, msg.To, fullMsg)
 	if err != nil {
-		logw.CtxErrorf(ctx, "emailw: failed to deliver email: %v", err)
+		logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "emailw: failed to deliver email: %v", err)
 		return err
 	}
 
-	logw.CtxInfof(ctx, "emailw: successfully sent email to %v | Subject: %s", msg.To, msg.Subject)
+	logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "emailw: successfully sent email to %v | Subject: %s", msg.To, msg.Subject)
 	return nil
 }
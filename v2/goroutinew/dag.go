@@ -0,0 +1,201 @@
+package goroutinew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrDependencyCycle is returned by Run when the steps depend on each other in a cycle.
+	ErrDependencyCycle = errors.New("goroutinew: dependency cycle")
+	// ErrDependencyFailed is recorded for every step skipped because one of its dependencies failed.
+	ErrDependencyFailed = errors.New("goroutinew: dependency failed")
+)
+
+// step is a registered handler together with its options.
+type step struct {
+	handler   StepFunc
+	dependsOn []string
+	timeout   time.Duration
+	retry     retryPolicy
+}
+
+// StepOption applies configuration to a single step registered with AddStep.
+type StepOption func(*step)
+
+// DependsOn makes the step wait until the given steps succeeded. Their results are available to
+// the handler through Result. A step with dependencies runs even without an input (input is nil).
+func DependsOn(keys ...string) StepOption {
+	return func(s *step) {
+		s.dependsOn = append(s.dependsOn, keys...)
+	}
+}
+
+// StepTimeout sets a deadline for every execution of the step's handler, overriding WithStepTimeout.
+func StepTimeout(d time.Duration) StepOption {
+	return func(s *step) {
+		s.timeout = d
+	}
+}
+
+// node is a step scheduled by Run.
+type node struct {
+	key        string
+	step       *step
+	jobs       []job
+	batch      bool
+	dependents []*node
+
+	waiting   int    // dependencies not finished yet
+	failedDep string // first dependency that failed or was skipped
+
+	remaining atomic.Int32 // jobs not finished yet
+	failed    atomic.Bool
+}
+
+// plan builds the DAG of the steps to run: every step with an input or dependencies, and the
+// steps they depend on. It rejects unknown dependencies and cycles.
+func (o *Orchestrator) plan() ([]*node, error) {
+	nodes := make(map[string]*node)
+	var add func(key string) error
+	add = func(key string) error {
+		if _, exists := nodes[key]; exists {
+			return nil
+		}
+		s := o.steps[key]
+		n := &node{key: key, step: s}
+		nodes[key] = n
+
+		if inputs, exists := o.batchInput[key]; exists {
+			n.batch = true
+			for i, input := range inputs {
+				n.jobs = append(n.jobs, job{key: key, batchIndex: i, input: input})
+			}
+		} else {
+			// Dependency-only steps run once with the input set by AddInput, if any.
+			n.jobs = []job{{key: key, batchIndex: -1, input: o.singleInput[key]}}
+		}
+
+		for _, dep := range s.dependsOn {
+			if _, exists := o.steps[dep]; !exists {
+				return fmt.Errorf("goroutinew: step [%s] depends on unregistered step [%s]", key, dep)
+			}
+			if err := add(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(o.steps))
+	for key, s := range o.steps {
+		_, single := o.singleInput[key]
+		_, batch := o.batchInput[key]
+		if single || batch || len(s.dependsOn) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if cycle := findCycle(nodes); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+	}
+
+	ordered := make([]*node, 0, len(nodes))
+	for _, key := range slices.Sorted(maps.Keys(nodes)) {
+		n := nodes[key]
+		for _, dep := range n.step.dependsOn {
+			if !slices.Contains(nodes[dep].dependents, n) {
+				nodes[dep].dependents = append(nodes[dep].dependents, n)
+				n.waiting++
+			}
+		}
+		ordered = append(ordered, n)
+	}
+	return ordered, nil
+}
+
+// findCycle returns the steps of a dependency cycle, starting and ending with the same step, or nil.
+func findCycle(nodes map[string]*node) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	var path []string
+
+	var visit func(key string) []string
+	visit = func(key string) []string {
+		state[key] = visiting
+		path = append(path, key)
+		for _, dep := range nodes[key].step.dependsOn {
+			switch state[dep] {
+			case visiting:
+				start := slices.Index(path, dep)
+				return append(slices.Clone(path[start:]), dep)
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[key] = done
+		return nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(nodes)) {
+		if state[key] == unvisited {
+			if cycle := visit(key); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// upstreamKey is the context key of the results of a step's dependencies.
+type upstreamKey struct{}
+
+// withUpstream returns ctx carrying the results of deps, which have all finished.
+func withUpstream(ctx context.Context, o *Orchestrator, deps []string) context.Context {
+	if len(deps) == 0 {
+		return ctx
+	}
+	upstream := make(map[string]any, len(deps))
+	for _, dep := range deps {
+		upstream[dep] = o.GetResp(dep)
+	}
+	return context.WithValue(ctx, upstreamKey{}, upstream)
+}
+
+// Result returns the result of the dependency key from the context of a step handler, converted to T.
+// It returns false if key is not a dependency of the step (see DependsOn) or its result is not a T.
+// The result of a batch step is a []any in input order.
+//
+// Example:
+//
+//	orc.AddStep("fetchOrders", func(ctx context.Context, _ any) (any, error) {
+//		user, ok := goroutinew.Result[*User](ctx, "fetchUser")
+//		if !ok {
+//			return nil, errors.New("missing user")
+//		}
+//		return repo.FindOrders(ctx, user.ID)
+//	}, goroutinew.DependsOn("fetchUser"))
+func Result[T any](ctx context.Context, key string) (T, bool) {
+	upstream, _ := ctx.Value(upstreamKey{}).(map[string]any)
+	value, ok := upstream[key].(T)
+	return value, ok
+}
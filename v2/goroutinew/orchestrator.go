@@ -1,12 +1,15 @@
 // Package goroutinew provides a robust, concurrent task orchestrator.
 // It supports executing single tasks and batched tasks simultaneously
 // within a bounded worker pool, ensuring strict timeouts and thread safety.
+// Steps may depend on each other (DependsOn); they then run as a DAG, with
+// independent branches in parallel and upstream results available through Result.
 package goroutinew
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -16,10 +19,12 @@ type StepFunc func(ctx context.Context, input any) (any, error)
 
 // Orchestrator manages the registration and execution of concurrent tasks.
 type Orchestrator struct {
-	maxWorkers int
-	timeout    time.Duration
+	maxWorkers  int
+	timeout     time.Duration
+	stepTimeout time.Duration
+	failFast    bool
 
-	steps       map[string]StepFunc
+	steps       map[string]*step
 	singleInput map[string]any
 	batchInput  map[string][]any
 
@@ -46,13 +51,30 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithStepTimeout sets a deadline for every single execution of a step handler (each batch item
+// separately), like v1's OptionKeyStepTimeoutMs. StepTimeout overrides it for one step.
+// Handlers must honor their context for the deadline to take effect.
+func WithStepTimeout(d time.Duration) Option {
+	return func(o *Orchestrator) {
+		o.stepTimeout = d
+	}
+}
+
+// WithFailFast cancels the context shared by all steps on the first error, like v1's OptionKeyBlockError.
+// Jobs not started yet are dropped and running handlers see their context canceled.
+func WithFailFast() Option {
+	return func(o *Orchestrator) {
+		o.failFast = true
+	}
+}
+
 // New initializes a new Goroutine Orchestrator.
 // Defaults to 10 concurrent workers and a 30-second total timeout.
 func New(opts ...Option) *Orchestrator {
 	o := &Orchestrator{
 		maxWorkers:  10,
 		timeout:     30 * time.Second,
-		steps:       make(map[string]StepFunc),
+		steps:       make(map[string]*step),
 		singleInput: make(map[string]any),
 		batchInput:  make(map[string][]any),
 		errors:      make([]error, 0),
@@ -64,8 +86,17 @@ func New(opts ...Option) *Orchestrator {
 }
 
 // AddStep registers a handler function under a specific key.
-func (o *Orchestrator) AddStep(key string, handler StepFunc) {
-	o.steps[key] = handler
+//
+// Example:
+//
+//	orc.AddStep("fetchUser", fetchUser)
+//	orc.AddStep("fetchOrders", fetchOrders, goroutinew.DependsOn("fetchUser"))
+func (o *Orchestrator) AddStep(key string, handler StepFunc, opts ...StepOption) {
+	s := &step{handler: handler}
+	for _, opt := range opts {
+		opt(s)
+	}
+	o.steps[key] = s
 }
 
 // AddInput assigns a single input to a registered step.
@@ -85,65 +116,108 @@ type job struct {
 	key        string
 	batchIndex int // -1 if single execution
 	input      any
-	handler    StepFunc
 }
 
 // Run executes all registered inputs concurrently.
 // It respects the maxWorkers limit and the context timeout.
+// A panicking handler is recovered and recorded as a *PanicError carrying its stack.
+//
+// Steps with dependencies start once all of them succeeded, even without an input of their own.
+// If a dependency fails, its dependents are skipped with ErrDependencyFailed.
+// Unknown dependencies and cycles are reported before any step runs.
 func (o *Orchestrator) Run(ctx context.Context) error {
+	nodes, err := o.plan()
+	if err != nil {
+		o.addError(err)
+		return err
+	}
+
 	timeoutCtx, cancel := context.WithTimeout(ctx, o.timeout)
 	defer cancel()
+	// runCtx is canceled by the first error in fail-fast mode.
+	runCtx, cancelRun := context.WithCancelCause(timeoutCtx)
+	defer cancelRun(nil)
 
-	var jobs []job
-
-	// 1. Queue Single Inputs
-	for key, input := range o.singleInput {
-		if handler, exists := o.steps[key]; exists {
-			jobs = append(jobs, job{key: key, batchIndex: -1, input: input, handler: handler})
+	// Every node is sent exactly once, so the buffer never fills.
+	ready := make(chan *node, len(nodes))
+	for _, n := range nodes {
+		if n.waiting == 0 {
+			ready <- n
 		}
 	}
 
-	// 2. Queue Batch Inputs
-	for key, inputs := range o.batchInput {
-		if handler, exists := o.steps[key]; exists {
-			// Pre-allocate slice to ensure thread-safe ordered insertion later
-			o.results.Store(key, make([]any, len(inputs)))
+	var (
+		mu sync.Mutex // guards waiting and failedDep of every node
+		wg sync.WaitGroup
+	)
+	semaphore := make(chan struct{}, o.maxWorkers)
 
-			for i, input := range inputs {
-				jobs = append(jobs, job{key: key, batchIndex: i, input: input, handler: handler})
+	// finish releases the dependents of n once all its jobs are done.
+	finish := func(n *node) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, d := range n.dependents {
+			if n.failed.Load() && d.failedDep == "" {
+				d.failedDep = n.key
+			}
+			d.waiting--
+			if d.waiting == 0 {
+				ready <- d
 			}
 		}
 	}
 
-	// 3. Execute Jobs with Bounded Concurrency
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, o.maxWorkers)
+	for range nodes {
+		n := <-ready
 
-	for _, j := range jobs {
-		wg.Add(1)
-		semaphore <- struct{}{} // Acquire worker slot
+		mu.Lock()
+		failedDep := n.failedDep
+		mu.Unlock()
+		if failedDep != "" {
+			n.failed.Store(true)
+			o.addError(fmt.Errorf("goroutinew step [%s] skipped: %w: [%s]", n.key, ErrDependencyFailed, failedDep))
+			finish(n)
+			continue
+		}
 
-		go func(currentJob job) {
-			defer wg.Done()
-			defer func() { <-semaphore }() // Release worker slot
+		if n.batch {
+			o.results.Store(n.key, make([]any, len(n.jobs)))
+		}
+		if len(n.jobs) == 0 {
+			finish(n)
+			continue
+		}
 
-			res, err := currentJob.handler(timeoutCtx, currentJob.input)
-			if err != nil {
-				o.addError(fmt.Errorf("goroutinew step [%s] failed: %w", currentJob.key, err))
-				return
+		stepCtx := withUpstream(runCtx, o, n.step.dependsOn)
+		n.remaining.Store(int32(len(n.jobs)))
+		for _, j := range n.jobs {
+			// Acquire a worker slot; once the run is over, the remaining jobs are not started.
+			select {
+			case semaphore <- struct{}{}:
+			case <-runCtx.Done():
+				n.failed.Store(true)
+				if n.remaining.Add(-1) == 0 {
+					finish(n)
+				}
+				continue
 			}
 
-			if currentJob.batchIndex == -1 {
-				// Store single result
-				o.results.Store(currentJob.key, res)
-			} else {
-				// Store batch result in the exact index (Thread-safe because each index is unique)
-				if val, ok := o.results.Load(currentJob.key); ok {
-					slice := val.([]any)
-					slice[currentJob.batchIndex] = res
+			wg.Add(1)
+			go func(currentJob job) {
+				defer wg.Done()
+				defer func() { <-semaphore }() // Release worker slot
+
+				if err := o.runJob(stepCtx, n.step, currentJob); err != nil {
+					n.failed.Store(true)
+					if o.failFast {
+						cancelRun(err)
+					}
 				}
-			}
-		}(j)
+				if n.remaining.Add(-1) == 0 {
+					finish(n)
+				}
+			}(j)
+		}
 	}
 
 	wg.Wait()
@@ -155,6 +229,84 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	return o.GetError()
 }
 
+// runJob executes a single job and stores its result.
+func (o *Orchestrator) runJob(ctx context.Context, s *step, j job) error {
+	timeout := o.stepTimeout
+	if s.timeout > 0 {
+		timeout = s.timeout
+	}
+
+	var (
+		res     any
+		err     error
+		attempt int
+	)
+	for attempt = 1; ; attempt++ {
+		res, err = call(withAttempt(ctx, attempt), s.handler, j.input, timeout)
+		if err == nil || !s.retry.shouldRetry(ctx, attempt, err) {
+			break
+		}
+		o.addError(&AttemptError{Step: j.key, Attempt: attempt, Err: err})
+		if !sleep(ctx, s.retry.delay(attempt)) {
+			err = fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+			break
+		}
+	}
+	if err != nil {
+		if attempt > 1 {
+			err = fmt.Errorf("goroutinew step [%s] failed after %d attempts: %w", j.key, attempt, err)
+		} else {
+			err = fmt.Errorf("goroutinew step [%s] failed: %w", j.key, err)
+		}
+		o.addError(err)
+		return err
+	}
+
+	if j.batchIndex == -1 {
+		// Store single result
+		o.results.Store(j.key, res)
+	} else {
+		// Store batch result in the exact index (Thread-safe because each index is unique)
+		if val, ok := o.results.Load(j.key); ok {
+			slice := val.([]any)
+			slice[j.batchIndex] = res
+		}
+	}
+	return nil
+}
+
+// call runs handler with the step timeout, converting a panic into a *PanicError.
+func call(ctx context.Context, handler StepFunc, input any, timeout time.Duration) (res any, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return handler(ctx, input)
+}
+
+// PanicError is recorded when a step handler panics.
+//
+// Example:
+//
+//	var panicErr *goroutinew.PanicError
+//	if errors.As(err, &panicErr) {
+//		logw.CtxErrorf(ctx, "step panicked: %v\n%s", panicErr.Value, panicErr.Stack)
+//	}
+type PanicError struct {
+	Value any    // Value is the value passed to panic.
+	Stack []byte // Stack is the stack trace of the panicking goroutine.
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
 func (o *Orchestrator) addError(err error) {
 	o.errMu.Lock()
 	defer o.errMu.Unlock()
@@ -162,16 +314,20 @@ func (o *Orchestrator) addError(err error) {
 }
 
 // GetError returns the first error encountered during execution, or nil if completely successful.
+// Failed attempts that were retried (*AttemptError) are not counted.
 func (o *Orchestrator) GetError() error {
 	o.errMu.Lock()
 	defer o.errMu.Unlock()
-	if len(o.errors) > 0 {
-		return o.errors[0]
+	for _, err := range o.errors {
+		if _, retried := err.(*AttemptError); !retried {
+			return err
+		}
 	}
 	return nil
 }
 
-// GetErrors returns all errors encountered by the workers.
+// GetErrors returns all errors encountered by the workers, including an *AttemptError for
+// every failed attempt that was retried.
 func (o *Orchestrator) GetErrors() []error {
 	o.errMu.Lock()
 	defer o.errMu.Unlock()
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -114,3 +115,258 @@ func TestOrchestrator_ErrorHandling(t *testing.T) {
 		t.Errorf("Expected exactly 1 error in GetErrors(), got %d", len(errs))
 	}
 }
+
+func TestOrchestrator_DependsOn(t *testing.T) {
+	ctx := context.Background()
+	orc := New(WithMaxWorkers(4), WithTimeout(2*time.Second))
+
+	var running, peak atomic.Int32
+	track := func() func() {
+		n := running.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(30 * time.Millisecond)
+		return func() { running.Add(-1) }
+	}
+
+	orc.AddStep("fetchUser", func(ctx context.Context, input any) (any, error) {
+		defer track()()
+		return "user_" + input.(string), nil
+	})
+	orc.AddStep("fetchOrders", func(ctx context.Context, input any) (any, error) {
+		defer track()()
+		user, ok := Result[string](ctx, "fetchUser")
+		if !ok {
+			return nil, errors.New("missing user")
+		}
+		return user + "_orders", nil
+	}, DependsOn("fetchUser"))
+	orc.AddStep("fetchProfile", func(ctx context.Context, input any) (any, error) {
+		defer track()()
+		user, _ := Result[string](ctx, "fetchUser")
+		return user + "_profile", nil
+	}, DependsOn("fetchUser"))
+	orc.AddStep("render", func(ctx context.Context, input any) (any, error) {
+		orders, _ := Result[string](ctx, "fetchOrders")
+		profile, _ := Result[string](ctx, "fetchProfile")
+		if _, ok := Result[string](ctx, "fetchUser"); ok {
+			return nil, errors.New("expected only direct dependencies to be visible")
+		}
+		return orders + "|" + profile, nil
+	}, DependsOn("fetchOrders", "fetchProfile"))
+
+	orc.AddInput("fetchUser", "42")
+
+	if err := orc.Run(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := orc.GetResp("render"); got != "user_42_orders|user_42_profile" {
+		t.Errorf("Expected the downstream step to combine upstream results, got %v", got)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("Expected the two independent branches to run concurrently, peak was %d", peak.Load())
+	}
+}
+
+func TestOrchestrator_DependencyErrors(t *testing.T) {
+	ctx := context.Background()
+	noop := func(ctx context.Context, input any) (any, error) { return nil, nil }
+
+	t.Run("cycle", func(t *testing.T) {
+		orc := New()
+		orc.AddStep("a", noop, DependsOn("c"))
+		orc.AddStep("b", noop, DependsOn("a"))
+		orc.AddStep("c", noop, DependsOn("b"))
+		err := orc.Run(ctx)
+		if !errors.Is(err, ErrDependencyCycle) || !strings.Contains(err.Error(), "a -> c -> b -> a") {
+			t.Errorf("Expected a dependency cycle error, got %v", err)
+		}
+	})
+
+	t.Run("unknown dependency", func(t *testing.T) {
+		orc := New()
+		orc.AddStep("a", noop, DependsOn("missing"))
+		if err := orc.Run(ctx); err == nil || !strings.Contains(err.Error(), "[missing]") {
+			t.Errorf("Expected an unknown dependency error, got %v", err)
+		}
+	})
+
+	t.Run("failed dependency", func(t *testing.T) {
+		orc := New()
+		var ran atomic.Bool
+		orc.AddStep("a", func(ctx context.Context, input any) (any, error) {
+			return nil, errors.New("boom")
+		})
+		orc.AddStep("b", func(ctx context.Context, input any) (any, error) {
+			ran.Store(true)
+			return nil, nil
+		}, DependsOn("a"))
+		orc.AddStep("c", noop, DependsOn("b"))
+		orc.AddInput("a", "payload")
+
+		err := orc.Run(ctx)
+		if err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("Expected the upstream error first, got %v", err)
+		}
+		if ran.Load() {
+			t.Error("Expected the dependent step to be skipped")
+		}
+		errs := orc.GetErrors()
+		if len(errs) != 3 || !errors.Is(errs[1], ErrDependencyFailed) || !errors.Is(errs[2], ErrDependencyFailed) {
+			t.Errorf("Expected both dependents to be recorded as skipped, got %v", errs)
+		}
+	})
+}
+
+func TestOrchestrator_FailFast(t *testing.T) {
+	ctx := context.Background()
+	orc := New(WithMaxWorkers(2), WithTimeout(2*time.Second), WithFailFast())
+
+	expectedErr := errors.New("downstream unavailable")
+	orc.AddStep("Failing", func(ctx context.Context, input any) (any, error) {
+		return nil, expectedErr
+	})
+	orc.AddStep("Slow", func(ctx context.Context, input any) (any, error) {
+		select {
+		case <-time.After(time.Second):
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	orc.AddInput("Failing", "payload")
+	orc.AddBatchInput("Slow", []any{1, 2, 3})
+
+	start := time.Now()
+	err := orc.Run(ctx)
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("Expected the first error to be returned, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the run to be canceled on the first error, took %v", elapsed)
+	}
+}
+
+func TestOrchestrator_PanicAndStepTimeout(t *testing.T) {
+	ctx := context.Background()
+	orc := New(WithTimeout(2*time.Second), WithStepTimeout(20*time.Millisecond))
+
+	slow := func(ctx context.Context, input any) (any, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	orc.AddStep("Panicking", func(ctx context.Context, input any) (any, error) {
+		panic("nil map")
+	})
+	orc.AddStep("TooSlow", slow)
+	orc.AddStep("AllowedSlow", slow, StepTimeout(time.Second))
+	orc.AddInput("Panicking", "payload")
+	orc.AddInput("TooSlow", "payload")
+	orc.AddInput("AllowedSlow", "payload")
+
+	if err := orc.Run(ctx); err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	var panicErr *PanicError
+	var timedOut bool
+	for _, err := range orc.GetErrors() {
+		if errors.As(err, &panicErr) && !strings.Contains(string(panicErr.Stack), "goroutinew") {
+			t.Errorf("Expected the panic stack to be captured, got %s", panicErr.Stack)
+		}
+		if errors.Is(err, context.DeadlineExceeded) && strings.Contains(err.Error(), "[TooSlow]") {
+			timedOut = true
+		}
+	}
+	if panicErr == nil || panicErr.Value != "nil map" {
+		t.Errorf("Expected the panic to be recorded as a PanicError, got %v", orc.GetErrors())
+	}
+	if !timedOut {
+		t.Errorf("Expected TooSlow to hit its step timeout, got %v", orc.GetErrors())
+	}
+	if orc.GetResp("AllowedSlow") != "done" {
+		t.Errorf("Expected StepTimeout to override the default, got %v", orc.GetResp("AllowedSlow"))
+	}
+}
+
+func TestOrchestrator_Retry(t *testing.T) {
+	ctx := context.Background()
+	errUnavailable := errors.New("503 service unavailable")
+	errBadRequest := errors.New("400 bad request")
+
+	orc := New(WithTimeout(2 * time.Second))
+	orc.AddStep("Flaky", func(ctx context.Context, input any) (any, error) {
+		if attempt := Attempt(ctx); attempt < 3 {
+			return nil, fmt.Errorf("attempt %d: %w", attempt, errUnavailable)
+		}
+		return "ok", nil
+	}, Retry(3), Backoff(time.Millisecond, 5*time.Millisecond))
+
+	var permanentCalls atomic.Int32
+	orc.AddStep("Permanent", func(ctx context.Context, input any) (any, error) {
+		permanentCalls.Add(1)
+		return nil, errBadRequest
+	}, Retry(3), Backoff(time.Millisecond, 5*time.Millisecond), RetryIf(func(err error) bool {
+		return errors.Is(err, errUnavailable)
+	}))
+
+	var exhaustedCalls atomic.Int32
+	orc.AddStep("Exhausted", func(ctx context.Context, input any) (any, error) {
+		exhaustedCalls.Add(1)
+		return nil, errUnavailable
+	}, Retry(2), Backoff(time.Millisecond, 5*time.Millisecond))
+
+	orc.AddInput("Flaky", "payload")
+	orc.AddInput("Permanent", "payload")
+	orc.AddInput("Exhausted", "payload")
+
+	err := orc.Run(ctx)
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	var attemptErr *AttemptError
+	if errors.As(err, &attemptErr) {
+		t.Errorf("Expected Run to report a final failure, not a retried attempt: %v", err)
+	}
+
+	if orc.GetResp("Flaky") != "ok" {
+		t.Errorf("Expected Flaky to succeed on its third attempt, got %v", orc.GetResp("Flaky"))
+	}
+	if permanentCalls.Load() != 1 {
+		t.Errorf("Expected non-retryable errors not to be retried, got %d calls", permanentCalls.Load())
+	}
+	if exhaustedCalls.Load() != 3 {
+		t.Errorf("Expected 1 call and 2 retries, got %d calls", exhaustedCalls.Load())
+	}
+
+	attempts := map[string]int{}
+	var exhausted error
+	for _, err := range orc.GetErrors() {
+		if errors.As(err, &attemptErr) {
+			attempts[attemptErr.Step]++
+		} else if strings.Contains(err.Error(), "[Exhausted]") {
+			exhausted = err
+		}
+	}
+	if attempts["Flaky"] != 2 || attempts["Exhausted"] != 2 || attempts["Permanent"] != 0 {
+		t.Errorf("Expected every retried attempt to be recorded, got %v", attempts)
+	}
+	if exhausted == nil || !errors.Is(exhausted, errUnavailable) || !strings.Contains(exhausted.Error(), "after 3 attempts") {
+		t.Errorf("Expected the final failure of Exhausted to be recorded, got %v", exhausted)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := retryPolicy{base: 100 * time.Millisecond, maxDelay: time.Second}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 10: time.Second} {
+		for range 20 {
+			if d := p.delay(attempt); d < want/2 || d > want {
+				t.Errorf("Expected the delay after attempt %d within [%v, %v], got %v", attempt, want/2, want, d)
+			}
+		}
+	}
+}
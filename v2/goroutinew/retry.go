@@ -0,0 +1,120 @@
+package goroutinew
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultBackoffBase = 100 * time.Millisecond
+	defaultBackoffMax  = 10 * time.Second
+)
+
+// retryPolicy holds the retry settings of a step. The zero value never retries.
+type retryPolicy struct {
+	retries   int
+	base      time.Duration
+	maxDelay  time.Duration
+	retryable func(error) bool
+}
+
+// Retry retries a failed execution of the step up to retries more times, waiting between attempts
+// with exponential backoff and jitter (see Backoff). Each batch item is retried on its own.
+//
+// Example:
+//
+//	orc.AddStep("fetchPrices", fetchPrices,
+//		goroutinew.Retry(3),
+//		goroutinew.Backoff(200*time.Millisecond, 2*time.Second),
+//		goroutinew.RetryIf(func(err error) bool { return !errors.Is(err, ErrNotFound) }),
+//	)
+func Retry(retries int) StepOption {
+	return func(s *step) {
+		s.retry.retries = retries
+	}
+}
+
+// Backoff sets the wait before the first retry; it doubles after every attempt up to maxDelay.
+// Each wait is randomized between half and all of its value, so failing callers do not retry in lockstep.
+// Defaults to 100ms and 10s.
+func Backoff(base, maxDelay time.Duration) StepOption {
+	return func(s *step) {
+		s.retry.base = base
+		s.retry.maxDelay = maxDelay
+	}
+}
+
+// RetryIf retries only the errors for which retryable returns true. By default every error is retried
+// while the run is not canceled.
+func RetryIf(retryable func(err error) bool) StepOption {
+	return func(s *step) {
+		s.retry.retryable = retryable
+	}
+}
+
+func (p retryPolicy) shouldRetry(ctx context.Context, attempt int, err error) bool {
+	if attempt > p.retries || ctx.Err() != nil {
+		return false
+	}
+	return p.retryable == nil || p.retryable(err)
+}
+
+// delay returns the randomized wait after the given failed attempt.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	base, limit := p.base, p.maxDelay
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	if limit <= 0 {
+		limit = defaultBackoffMax
+	}
+	d := base
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	return d/2 + rand.N(d/2+1)
+}
+
+// sleep waits for d and reports whether ctx is still active.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// AttemptError is recorded in GetErrors for every failed attempt of a step that was retried.
+type AttemptError struct {
+	Step    string
+	Attempt int
+	Err     error
+}
+
+func (e *AttemptError) Error() string {
+	return fmt.Sprintf("goroutinew step [%s] attempt %d failed: %v", e.Step, e.Attempt, e.Err)
+}
+
+func (e *AttemptError) Unwrap() error {
+	return e.Err
+}
+
+// attemptKey is the context key of the current attempt number.
+type attemptKey struct{}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Attempt returns the attempt number (starting at 1) of the step execution running with ctx,
+// or 0 outside of a step handler.
+func Attempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/AndreeJait/go-utility/v2/logw"
 )
 
+const loggerName = "gracefulw"
+
 // CleanupFunc defines the signature for functions executed during the shutdown process.
 // It receives a context with a timeout to ensure operations do not hang indefinitely.
 type CleanupFunc func(ctx context.Context) error
@@ -45,7 +47,7 @@ func Register(name string, cleanup CleanupFunc) {
 		Cleanup: cleanup,
 	})
 
-	logw.Infof("Registered service '%s' for graceful shutdown", name)
+	logw.Named(loggerName).Infof("Registered service '%s' for graceful shutdown", name)
 }
 
 // Start executes a blocking function (like starting an HTTP server) in a background goroutine,
@@ -57,7 +59,7 @@ func Start(startFunc func() error, shutdownTimeout time.Duration) {
 		err := startFunc()
 		// We ignore http.ErrServerClosed because it is expected when the server is intentionally shut down
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logw.Errorf("Service stopped unexpectedly: %v", err)
+			logw.Named(loggerName).Errorf("Service stopped unexpectedly: %v", err)
 		}
 	}()
 
@@ -74,7 +76,7 @@ func Wait(timeout time.Duration) {
 
 	// Block execution until the signal is caught
 	<-ctx.Done()
-	logw.Warning("Received termination signal, initiating graceful shutdown...")
+	logw.Named(loggerName).Warning("Received termination signal, initiating graceful shutdown...")
 
 	// Create a new context specifically for the shutdown process with a hard deadline
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -101,11 +103,11 @@ func Execute(ctx context.Context) {
 		go func(t Task) {
 			defer wg.Done()
 
-			logw.Infof("Stopping service: %s...", t.Name)
+			logw.Named(loggerName).Infof("Stopping service: %s...", t.Name)
 			if err := t.Cleanup(ctx); err != nil {
-				logw.Errorf("Failed to stop service '%s': %v", t.Name, err)
+				logw.Named(loggerName).Errorf("Failed to stop service '%s': %v", t.Name, err)
 			} else {
-				logw.Infof("Service '%s' stopped successfully", t.Name)
+				logw.Named(loggerName).Infof("Service '%s' stopped successfully", t.Name)
 			}
 		}(task)
 	}
@@ -120,8 +122,8 @@ func Execute(ctx context.Context) {
 	// Block until either all tasks are done OR the timeout context expires
 	select {
 	case <-done:
-		logw.Info("Graceful shutdown completed successfully. All services stopped.")
+		logw.Named(loggerName).Info("Graceful shutdown completed successfully. All services stopped.")
 	case <-ctx.Done():
-		logw.Error("Graceful shutdown timed out. Forcing exit!")
+		logw.Named(loggerName).Error("Graceful shutdown timed out. Forcing exit!")
 	}
 }
@@ -12,6 +12,8 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+const loggerName = "echow"
+
 // Config holds the configuration options for initializing the Echo HTTP server.
 type Config struct {
 	DebugMode     bool
@@ -56,7 +58,7 @@ func loggerMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
 			req := c.Request()
-			ctx := logw.InjectLogID(req.Context())
+			ctx := logw.InjectLogID(logw.ContextFromTraceparent(req.Context(), req.Header.Get("traceparent")))
 
 			// Inject the enriched context back into the Echo request
 			c.SetRequest(req.WithContext(ctx))
@@ -77,7 +79,7 @@ func loggerMiddleware() echo.MiddlewareFunc {
 				c.Echo().HTTPErrorHandler(c, err)
 			}
 
-			logw.CtxInfof(ctx, "[ECHO] %s %s | Status: %d | Latency: %v", req.Method, req.URL.Path, status, time.Since(start))
+			logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[ECHO] %s %s | Status: %d | Latency: %v", req.Method, req.URL.Path, status, time.Since(start))
 
 			// Return nil since errors are fully handled above
 			return nil
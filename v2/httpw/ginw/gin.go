@@ -11,6 +11,8 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+const loggerName = "ginw"
+
 // Config holds the configuration options for initializing the Gin HTTP engine.
 type Config struct {
 	DebugMode     bool
@@ -33,7 +35,7 @@ func New(cfg *Config) *gin.Engine {
 	// Global Logger and Error Catcher Middleware
 	r.Use(func(c *gin.Context) {
 		req := c.Request
-		ctx := logw.InjectLogID(req.Context())
+		ctx := logw.InjectLogID(logw.ContextFromTraceparent(req.Context(), req.Header.Get("traceparent")))
 		c.Request = req.WithContext(ctx)
 
 		start := time.Now()
@@ -56,7 +58,7 @@ func New(cfg *Config) *gin.Engine {
 			}
 		}
 
-		logw.CtxInfof(ctx, "[GIN] %s %s | Status: %d | Latency: %v", req.Method, req.URL.Path, c.Writer.Status(), time.Since(start))
+		logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[GIN] %s %s | Status: %d | Latency: %v", req.Method, req.URL.Path, c.Writer.Status(), time.Since(start))
 	})
 
 	// Mount Swagger UI if enabled
@@ -13,6 +13,8 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+const loggerName = "muxw"
+
 // Config holds the configuration options for initializing the Gorilla Mux router.
 type Config struct {
 	DebugMode     bool // Ditambahkan agar seragam dengan Echo dan Gin
@@ -45,7 +47,7 @@ func New(cfg *Config) *mux.Router {
 // and logs the execution latency.
 func loggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := logw.InjectLogID(r.Context())
+		ctx := logw.InjectLogID(logw.ContextFromTraceparent(r.Context(), r.Header.Get("traceparent")))
 		r = r.WithContext(ctx)
 
 		start := time.Now()
@@ -53,7 +55,7 @@ func loggerMiddleware(next http.Handler) http.Handler {
 		// Execute the actual handler logic
 		next.ServeHTTP(w, r)
 
-		logw.CtxInfof(ctx, "[MUX] %s %s | Latency: %v", r.Method, r.URL.Path, time.Since(start))
+		logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[MUX] %s %s | Latency: %v", r.Method, r.URL.Path, time.Since(start))
 	})
 }
 
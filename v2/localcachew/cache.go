@@ -9,14 +9,20 @@ import (
 	"github.com/AndreeJait/go-utility/v2/logw" // Sesuaikan dengan module kamu
 )
 
+const loggerName = "localcachew"
+
 var (
 	ErrKeyNotFound = errors.New("localcachew: key not found or expired")
+	ErrCostTooHigh = errors.New("localcachew: item is larger than the cache")
 
 	// globalCache menyimpan instance tunggal (singleton) dari local cache
-	globalCache *localCache
+	globalCache *LocalCache
 	initOnce    sync.Once
 )
 
+// defaultCleanupInterval is used by New without WithCleanupInterval and by the package-level safety net.
+const defaultCleanupInterval = 5 * time.Minute
+
 // Item represents a single cached object along with its expiration time.
 type Item struct {
 	Value      any
@@ -25,7 +31,10 @@ type Item struct {
 
 // SetConfig holds the configuration applied during a SetKV operation.
 type SetConfig struct {
-	TTL time.Duration
+	TTL         time.Duration
+	SoftTTL     time.Duration
+	NegativeTTL time.Duration
+	Cost        int64
 }
 
 // Option is a functional option for configuring SetKV behavior.
@@ -38,154 +47,304 @@ func WithTTL(ttl time.Duration) Option {
 	}
 }
 
-type localCache struct {
-	mu          sync.RWMutex
-	items       map[string]Item
-	cleanupFreq time.Duration
-	stopCleanup chan struct{}
+// WithSoftTTL makes GetOrLoad refresh the item in the background once it is older than ttl,
+// while still serving the stale value (stale-while-revalidate). It should be shorter than WithTTL.
+func WithSoftTTL(ttl time.Duration) Option {
+	return func(c *SetConfig) {
+		c.SoftTTL = ttl
+	}
+}
+
+// WithNegativeTTL makes GetOrLoad cache loader errors for ttl, so a failing backend is not hit on every call.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *SetConfig) {
+		c.NegativeTTL = ttl
+	}
+}
+
+// WithCost sets the cost of the cached item, overriding the cache's size function.
+func WithCost(cost int64) Option {
+	return func(c *SetConfig) {
+		c.Cost = cost
+	}
+}
+
+// cacheConfig holds the settings of a cache instance.
+type cacheConfig struct {
+	maxEntries      int
+	maxCost         int64
+	sizeFn          func(key string, value any) int64
+	policy          EvictionPolicy
+	cleanupInterval time.Duration
+	shards          int
+	redis           *redisConfig
+	snapshotPath    string
+}
+
+// CacheOption is a functional option for configuring a cache instance created with New.
+type CacheOption func(*cacheConfig)
+
+// WithMaxEntries bounds the cache to n entries; the eviction policy drops entries beyond it.
+func WithMaxEntries(n int) CacheOption {
+	return func(c *cacheConfig) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxCost bounds the total cost of the cached entries. The cost of an entry is set with WithCost,
+// computed by WithSizeFunc or NewSizedCache, or 1 otherwise.
+func WithMaxCost(maxCost int64) CacheOption {
+	return func(c *cacheConfig) {
+		c.maxCost = maxCost
+	}
+}
+
+// WithSizeFunc computes the cost of every entry of caches created with New or Init, e.g. its size in bytes.
+// Typed caches pass their size function to NewSizedCache instead.
+func WithSizeFunc(fn func(key string, value any) int64) CacheOption {
+	return func(c *cacheConfig) {
+		c.sizeFn = fn
+	}
+}
+
+// WithEvictionPolicy selects how a bounded cache chooses entries to evict. The default is LRU.
+func WithEvictionPolicy(p EvictionPolicy) CacheOption {
+	return func(c *cacheConfig) {
+		c.policy = p
+	}
+}
+
+// WithCleanupInterval sets how often expired entries are removed in the background. 0 disables it.
+func WithCleanupInterval(d time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.cleanupInterval = d
+	}
+}
+
+// WithShards spreads the entries over n shards (rounded up to a power of two), each with its own lock.
+// Unbounded caches use defaultShards; bounded caches default to a single shard so eviction is exact,
+// and split WithMaxEntries and WithMaxCost evenly between shards when n > 1.
+func WithShards(n int) CacheOption {
+	return func(c *cacheConfig) {
+		c.shards = n
+	}
+}
+
+// defaultShards is the shard count of unbounded caches.
+const defaultShards = 16
+
+// shardCount returns the number of shards, always a power of two.
+func (c *cacheConfig) shardCount() int {
+	n := c.shards
+	if n <= 0 {
+		n = 1
+		if c.maxEntries <= 0 && c.maxCost <= 0 {
+			n = defaultShards
+		}
+	}
+	pow := 1
+	for pow < n {
+		pow <<= 1
+	}
+	return pow
+}
+
+func newCacheConfig(opts []CacheOption) *cacheConfig {
+	cfg := &cacheConfig{cleanupInterval: defaultCleanupInterval}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// LocalCache is an independent cache instance with the same API as the package-level functions.
+// Use Cache for typed keys and values.
+type LocalCache struct {
+	cache *Cache[string, any]
+}
+
+// New creates an independent cache instance. Without WithMaxEntries or WithMaxCost it is unbounded.
+//
+// Example:
+//
+//	sessions := localcachew.New(
+//		localcachew.WithMaxEntries(10_000),
+//		localcachew.WithEvictionPolicy(localcachew.TinyLFU),
+//	)
+//	gracefulw.Register("SessionCache", func(ctx context.Context) error {
+//		sessions.StopCleanup()
+//		return nil
+//	})
+func New(opts ...CacheOption) *LocalCache {
+	return &LocalCache{cache: NewCache[string, any](opts...)}
 }
 
 // Init menginisialisasi global cache. Fungsi ini idealnya dipanggil sekali di main.go.
 // cleanupInterval menentukan seberapa sering Garbage Collector berjalan di background.
-func Init(cleanupInterval time.Duration) {
+// opts may bound the global cache, e.g. Init(time.Minute, WithMaxEntries(100_000)).
+func Init(cleanupInterval time.Duration, opts ...CacheOption) {
 	initOnce.Do(func() {
-		globalCache = &localCache{
-			items:       make(map[string]Item),
-			cleanupFreq: cleanupInterval,
-			stopCleanup: make(chan struct{}),
-		}
+		globalCache = New(append(opts[:len(opts):len(opts)], WithCleanupInterval(cleanupInterval))...)
 
 		if cleanupInterval > 0 {
-			go globalCache.startBackgroundCleanup()
-			logw.Infof("Local cache initialized with %v cleanup interval", cleanupInterval)
+			logw.Named(loggerName).Infof("Local cache initialized with %v cleanup interval", cleanupInterval)
 		} else {
-			logw.Info("Local cache initialized WITHOUT background cleanup")
+			logw.Named(loggerName).Info("Local cache initialized WITHOUT background cleanup")
 		}
 	})
 }
 
 // getCache memastikan globalCache selalu tersedia meskipun Init() lupa dipanggil (Safety Net).
-func getCache() *localCache {
+func getCache() *LocalCache {
 	if globalCache == nil {
-		Init(5 * time.Minute) // Default aman jika lupa di-init
+		Init(defaultCleanupInterval) // Default aman jika lupa di-init
 	}
 	return globalCache
 }
 
-func (c *localCache) startBackgroundCleanup() {
-	ticker := time.NewTicker(c.cleanupFreq)
-	defer ticker.Stop()
+// SetKV inserts or updates an item. If the cache is bounded, other entries may be evicted.
+func (c *LocalCache) SetKV(ctx context.Context, key string, value any, opts ...Option) error {
+	config := &SetConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	ctx, cancel := c.cache.redisContext(ctx)
+	defer cancel()
+	return c.cache.set(ctx, key, value, config)
+}
 
-	for {
-		select {
-		case <-ticker.C:
-			c.deleteExpired()
-		case <-c.stopCleanup:
-			logw.Info("Local cache background cleanup stopped gracefully.")
-			return
-		}
+// Get retrieves an item, or ErrKeyNotFound if it is missing or expired.
+func (c *LocalCache) Get(ctx context.Context, key string) (any, error) {
+	ctx, cancel := c.cache.redisContext(ctx)
+	defer cancel()
+	value, found := c.cache.get(ctx, key)
+	if !found {
+		return nil, ErrKeyNotFound
 	}
+	return value, nil
 }
 
-func (c *localCache) deleteExpired() {
-	now := time.Now().UnixNano()
+// GetOrLoad returns the cached value of key, or calls loader once for all concurrent callers and caches
+// its result. See Cache.GetOrLoad for WithSoftTTL and WithNegativeTTL.
+func (c *LocalCache) GetOrLoad(ctx context.Context, key string, loader Loader[string, any], opts ...Option) (any, error) {
+	return c.cache.GetOrLoad(ctx, key, loader, opts...)
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// IsKeyExists checks if a key exists and is still valid.
+func (c *LocalCache) IsKeyExists(ctx context.Context, key string) bool {
+	_, err := c.Get(ctx, key)
+	return err == nil
+}
 
-	for key, item := range c.items {
-		if item.Expiration > 0 && now > item.Expiration {
-			delete(c.items, key)
-		}
-	}
+// Delete forcefully removes a key.
+func (c *LocalCache) Delete(ctx context.Context, key string) error {
+	ctx, cancel := c.cache.redisContext(ctx)
+	defer cancel()
+	return c.cache.delete(ctx, key)
 }
 
-// SetKV inserts or updates an item in the global cache.
-func SetKV(ctx context.Context, key string, value any, opts ...Option) error {
-	c := getCache()
+// Length returns the total number of items currently in the cache, including expired ones not yet cleaned up.
+func (c *LocalCache) Length() int {
+	return c.cache.Len()
+}
 
-	config := &SetConfig{}
-	for _, opt := range opts {
-		opt(config)
-	}
+// Clear cleanly wipes the entire cache.
+func (c *LocalCache) Clear() error {
+	c.cache.Clear()
+	return nil
+}
 
-	var exp int64
-	if config.TTL > 0 {
-		exp = time.Now().Add(config.TTL).UnixNano()
-	}
+// Stats returns the counters of the cache since it was created.
+func (c *LocalCache) Stats() CacheStats {
+	return c.cache.Stats()
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// OnEvict registers fn to be called for every entry removed from the cache. See Cache.OnEvict.
+func (c *LocalCache) OnEvict(fn func(key string, value any, reason EvictReason)) {
+	c.cache.OnEvict(fn)
+}
 
-	c.items[key] = Item{
-		Value:      value,
-		Expiration: exp,
-	}
+// StopCleanup halts the background garbage collector and writes the snapshot if WithSnapshot is set.
+// It is safe to call more than once.
+func (c *LocalCache) StopCleanup() {
+	c.cache.StopCleanup()
+}
 
-	return nil
+// Close is StopCleanup returning the snapshot error. Its signature matches gracefulw.CleanupFunc.
+func (c *LocalCache) Close(ctx context.Context) error {
+	return c.cache.Close(ctx)
+}
+
+// SetKV inserts or updates an item in the global cache.
+func SetKV(ctx context.Context, key string, value any, opts ...Option) error {
+	return getCache().SetKV(ctx, key, value, opts...)
 }
 
 // Get retrieves an item from the global cache.
 // Uses RLock, so multiple endpoints can read simultaneously without blocking.
 func Get(ctx context.Context, key string) (any, error) {
-	c := getCache()
-
-	c.mu.RLock()
-	item, found := c.items[key]
-	c.mu.RUnlock()
-
-	if !found {
-		return nil, ErrKeyNotFound
-	}
-
-	// Check expiration (Lazy Eviction)
-	if item.Expiration > 0 && time.Now().UnixNano() > item.Expiration {
-		return nil, ErrKeyNotFound
-	}
+	return getCache().Get(ctx, key)
+}
 
-	return item.Value, nil
+// GetOrLoad returns the cached value of key from the global cache, or loads and caches it.
+// Concurrent misses for the same key call loader only once.
+//
+// Example:
+//
+//	v, err := localcachew.GetOrLoad(ctx, "product:"+id, func(ctx context.Context, key string) (any, error) {
+//		return repo.FindProduct(ctx, id)
+//	}, localcachew.WithTTL(5*time.Minute), localcachew.WithSoftTTL(time.Minute))
+func GetOrLoad(ctx context.Context, key string, loader Loader[string, any], opts ...Option) (any, error) {
+	return getCache().GetOrLoad(ctx, key, loader, opts...)
 }
 
 // IsKeyExists checks if a key exists and is still valid in the global cache.
 func IsKeyExists(ctx context.Context, key string) bool {
-	_, err := Get(ctx, key)
-	return err == nil
+	return getCache().IsKeyExists(ctx, key)
 }
 
 // Delete forcefully removes a key from the global cache.
 func Delete(ctx context.Context, key string) error {
-	c := getCache()
-
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	delete(c.items, key)
-	return nil
+	return getCache().Delete(ctx, key)
 }
 
 // Length returns the total number of items currently in the global cache.
 func Length() int {
-	c := getCache()
-
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.items)
+	return getCache().Length()
 }
 
 // Clear cleanly wipes the entire global cache.
 func Clear() error {
-	c := getCache()
+	return getCache().Clear()
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.items = make(map[string]Item)
-	return nil
+// Stats returns the counters of the global cache.
+func Stats() CacheStats {
+	return getCache().Stats()
+}
+
+// OnEvict registers fn to be called for every entry removed from the global cache.
+func OnEvict(fn func(key string, value any, reason EvictReason)) {
+	getCache().OnEvict(fn)
 }
 
 // StopCleanup halts the background garbage collector.
 // Highly recommended to hook this into gracefulw!
 func StopCleanup() {
-	if globalCache != nil && globalCache.stopCleanup != nil {
-		close(globalCache.stopCleanup)
+	if globalCache != nil {
+		globalCache.StopCleanup()
+	}
+}
+
+// Close halts the global cache and writes its snapshot if Init was given WithSnapshot.
+// Its signature matches gracefulw.CleanupFunc.
+//
+// Example:
+//
+//	gracefulw.Register("LocalCache", localcachew.Close)
+func Close(ctx context.Context) error {
+	if globalCache == nil {
+		return nil
 	}
+	return globalCache.Close(ctx)
 }
@@ -2,9 +2,17 @@ package localcachew
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // setupTest memastikan cache bersih sebelum test dijalankan
@@ -91,3 +99,566 @@ func TestGlobalCache_SafetyNetWithoutInit(t *testing.T) {
 		t.Errorf("Safety net failed to retrieve value")
 	}
 }
+
+func TestNew_IndependentInstances(t *testing.T) {
+	ctx := context.Background()
+	a, b := New(WithCleanupInterval(0)), New(WithCleanupInterval(0))
+
+	_ = a.SetKV(ctx, "key", "a")
+	if b.IsKeyExists(ctx, "key") {
+		t.Errorf("Expected instances not to share entries")
+	}
+	if v, _ := a.Get(ctx, "key"); v != "a" {
+		t.Errorf("Expected 'a', got %v", v)
+	}
+	a.StopCleanup()
+	a.StopCleanup() // must not panic
+}
+
+func TestNew_EvictionPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	lru := New(WithMaxEntries(2), WithEvictionPolicy(LRU), WithCleanupInterval(0))
+	_ = lru.SetKV(ctx, "a", 1)
+	_ = lru.SetKV(ctx, "b", 2)
+	_, _ = lru.Get(ctx, "a")
+	_ = lru.SetKV(ctx, "c", 3)
+	if lru.IsKeyExists(ctx, "b") || !lru.IsKeyExists(ctx, "a") || lru.Length() != 2 {
+		t.Errorf("Expected LRU to evict the least recently used key 'b'")
+	}
+
+	lfu := New(WithMaxEntries(2), WithEvictionPolicy(LFU), WithCleanupInterval(0))
+	_ = lfu.SetKV(ctx, "a", 1)
+	_ = lfu.SetKV(ctx, "b", 2)
+	_, _ = lfu.Get(ctx, "b")
+	_, _ = lfu.Get(ctx, "b")
+	_ = lfu.SetKV(ctx, "c", 3)
+	if lfu.IsKeyExists(ctx, "a") || !lfu.IsKeyExists(ctx, "b") {
+		t.Errorf("Expected LFU to evict the least frequently used key 'a'")
+	}
+
+	// A scan of one-hit keys must not flush the frequently used ones out of a TinyLFU cache.
+	tiny := New(WithMaxEntries(100), WithEvictionPolicy(TinyLFU), WithCleanupInterval(0))
+	for i := 0; i < 50; i++ {
+		_ = tiny.SetKV(ctx, fmt.Sprintf("hot-%d", i), i)
+	}
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 50; i++ {
+			_, _ = tiny.Get(ctx, fmt.Sprintf("hot-%d", i))
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		_ = tiny.SetKV(ctx, fmt.Sprintf("scan-%d", i), i)
+	}
+	hot := 0
+	for i := 0; i < 50; i++ {
+		if tiny.IsKeyExists(ctx, fmt.Sprintf("hot-%d", i)) {
+			hot++
+		}
+	}
+	if tiny.Length() != 100 || hot < 45 {
+		t.Errorf("Expected TinyLFU to keep the hot keys, kept %d of 50 (length %d)", hot, tiny.Length())
+	}
+}
+
+func TestNew_MaxCost(t *testing.T) {
+	ctx := context.Background()
+	c := New(
+		WithMaxCost(10),
+		WithSizeFunc(func(key string, value any) int64 { return int64(len(value.(string))) }),
+		WithCleanupInterval(0),
+	)
+
+	_ = c.SetKV(ctx, "a", "1234")
+	_ = c.SetKV(ctx, "b", "1234")
+	_ = c.SetKV(ctx, "c", "1234")
+	if c.IsKeyExists(ctx, "a") || c.Length() != 2 {
+		t.Errorf("Expected the oldest entry to be evicted once the cost exceeds 10")
+	}
+	if err := c.SetKV(ctx, "big", "12345678901"); !errors.Is(err, ErrCostTooHigh) {
+		t.Errorf("Expected ErrCostTooHigh, got %v", err)
+	}
+	_ = c.SetKV(ctx, "d", "x", WithCost(9))
+	if c.Length() != 1 {
+		t.Errorf("Expected WithCost to override the size function, got %d entries", c.Length())
+	}
+
+	blobs := NewSizedCache(func(key string, value []byte) int64 { return int64(len(value)) },
+		WithMaxCost(10), WithCleanupInterval(0))
+	_ = blobs.Set("a", make([]byte, 6))
+	_ = blobs.Set("b", make([]byte, 6))
+	if _, ok := blobs.Get("a"); ok || blobs.Len() != 1 {
+		t.Errorf("Expected the typed size function to bound the cost, got %d entries", blobs.Len())
+	}
+}
+
+func TestCache_Typed(t *testing.T) {
+	type user struct{ Name string }
+	ctx := context.Background()
+	users := NewCache[int, *user](WithCleanupInterval(0))
+
+	_ = users.Set(1, &user{Name: "ana"})
+	_ = users.Set(2, &user{Name: "budi"}, WithTTL(time.Millisecond))
+	if u, ok := users.Get(1); !ok || u.Name != "ana" {
+		t.Errorf("Expected user 1, got %v", u)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := users.Get(2); ok {
+		t.Errorf("Expected user 2 to expire")
+	}
+	if keys := users.Keys(); len(keys) != 1 || keys[0] != 1 {
+		t.Errorf("Expected only key 1 to be listed, got %v", keys)
+	}
+
+	loads := 0
+	loader := func(ctx context.Context, id int) (*user, error) {
+		loads++
+		return &user{Name: fmt.Sprintf("user-%d", id)}, nil
+	}
+	for i := 0; i < 2; i++ {
+		if u, err := users.GetOrLoad(ctx, 3, loader); err != nil || u.Name != "user-3" {
+			t.Errorf("Expected user-3 from GetOrLoad, got %v (err %v)", u, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected the loader to run once, ran %d times", loads)
+	}
+	if _, err := users.GetOrLoad(ctx, 4, func(context.Context, int) (*user, error) {
+		return nil, ErrKeyNotFound
+	}); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected the loader error, got %v", err)
+	}
+
+	seen := map[int]string{}
+	users.Range(func(id int, u *user) bool {
+		users.Delete(id) // the cache may be modified while ranging
+		seen[id] = u.Name
+		return true
+	})
+	if len(seen) != 2 || seen[1] != "ana" || len(users.Keys()) != 0 {
+		t.Errorf("Expected Range to visit users 1 and 3, got %v", seen)
+	}
+}
+
+func TestGetOrLoad_SingleflightAndStaleWhileRevalidate(t *testing.T) {
+	setupTest()
+	ctx := context.Background()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (any, error) {
+		n := loads.Add(1)
+		<-release
+		return fmt.Sprintf("v%d", n), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan any, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _ := GetOrLoad(ctx, "popular", loader, WithTTL(time.Minute), WithSoftTTL(20*time.Millisecond))
+			results <- v
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+	for v := range results {
+		if v != "v1" {
+			t.Errorf("Expected every caller to share v1, got %v", v)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Fatalf("Expected a single load for concurrent misses, got %d", loads.Load())
+	}
+
+	// Past the soft TTL the stale value is served while one background refresh runs.
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if v, _ := GetOrLoad(ctx, "popular", loader, WithTTL(time.Minute), WithSoftTTL(20*time.Millisecond)); v != "v1" {
+			t.Errorf("Expected the stale value v1, got %v", v)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := Get(ctx, "popular"); v == "v2" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v, _ := Get(ctx, "popular"); v != "v2" || loads.Load() != 2 {
+		t.Errorf("Expected one background refresh to store v2, got %v after %d loads", v, loads.Load())
+	}
+}
+
+func TestGetOrLoad_NegativeCaching(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache[string, int](WithCleanupInterval(0))
+
+	errDown := errors.New("db down")
+	calls := 0
+	loader := func(ctx context.Context, key string) (int, error) {
+		calls++
+		return 0, errDown
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetOrLoad(ctx, "k", loader, WithNegativeTTL(30*time.Millisecond)); !errors.Is(err, errDown) {
+			t.Errorf("Expected the loader error, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the error to be cached, loader ran %d times", calls)
+	}
+	if stats := c.Stats(); stats.NegativeHits != 2 || stats.Hits != 0 {
+		t.Errorf("Expected cached errors to count as negative hits only, got %+v", stats)
+	}
+	if _, ok := c.Get("k"); ok {
+		t.Errorf("Expected a cached error not to be returned by Get")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	_, _ = c.GetOrLoad(ctx, "k", loader, WithNegativeTTL(30*time.Millisecond))
+	if calls != 2 {
+		t.Errorf("Expected the loader to run again after the negative TTL, ran %d times", calls)
+	}
+
+	if _, err := c.GetOrLoad(ctx, "p", func(context.Context, string) (int, error) { panic("boom") }); err == nil {
+		t.Errorf("Expected a panicking loader to return an error")
+	}
+}
+
+func TestGetOrLoad_FailedRefreshBackoff(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache[string, string](WithCleanupInterval(0))
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context, key string) (string, error) {
+		if loads.Add(1) > 1 {
+			return "", errors.New("backend down")
+		}
+		return "v1", nil
+	}
+	opts := []Option{WithTTL(time.Minute), WithSoftTTL(30 * time.Millisecond)}
+
+	_, _ = c.GetOrLoad(ctx, "k", loader, opts...)
+	time.Sleep(40 * time.Millisecond)
+	_, _ = c.GetOrLoad(ctx, "k", loader, opts...)
+	deadline := time.Now().Add(time.Second)
+	for loads.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond) // let the failed refresh reschedule itself
+
+	for i := 0; i < 10; i++ {
+		if v, _ := c.GetOrLoad(ctx, "k", loader, opts...); v != "v1" {
+			t.Errorf("Expected the stale value v1, got %v", v)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := loads.Load(); n != 2 {
+		t.Errorf("Expected a failed refresh to be deferred, got %d loads", n)
+	}
+
+	e, _ := c.shard("k").lookup("k")
+	if e.refreshFailures != 1 || time.Until(time.Unix(0, e.refreshAt)) <= 0 {
+		t.Errorf("Expected the next refresh to be rescheduled, got %d failures and refreshAt in %v",
+			e.refreshFailures, time.Until(time.Unix(0, e.refreshAt)))
+	}
+}
+
+func TestCache_ShardsAndExpiryHeap(t *testing.T) {
+	c := NewCache[int, int](WithShards(6), WithMaxEntries(800), WithCleanupInterval(0))
+	if len(c.shards) != 8 {
+		t.Fatalf("Expected 6 shards to round up to 8, got %d", len(c.shards))
+	}
+	for i := 0; i < 2000; i++ {
+		_ = c.Set(i, i)
+	}
+	if n := c.Len(); n > 800 || n < 700 {
+		t.Errorf("Expected about 800 entries across shards, got %d", n)
+	}
+
+	c = NewCache[int, int](WithCleanupInterval(0))
+	for i := 0; i < 1000; i++ {
+		ttl := time.Hour
+		if i%2 == 0 {
+			ttl = time.Millisecond
+		}
+		_ = c.Set(i, i, WithTTL(ttl))
+	}
+	_ = c.Set(0, 0) // overwriting without a TTL removes the key from the expiry heap
+	c.Delete(2)
+	time.Sleep(5 * time.Millisecond)
+	for _, s := range c.shards {
+		s.deleteExpired()
+	}
+	if n := c.Len(); n != 501 {
+		t.Errorf("Expected 501 entries after cleanup, got %d", n)
+	}
+	if _, ok := c.Get(0); !ok {
+		t.Errorf("Expected key 0 to survive without a TTL")
+	}
+}
+
+// legacyCache is the former single-lock implementation, kept as a benchmark baseline.
+type legacyCache struct {
+	mu    sync.RWMutex
+	items map[string]Item
+}
+
+func (c *legacyCache) set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = Item{Value: value, Expiration: time.Now().Add(ttl).UnixNano()}
+}
+
+func (c *legacyCache) get(key string) (any, bool) {
+	c.mu.RLock()
+	item, found := c.items[key]
+	c.mu.RUnlock()
+	if !found || (item.Expiration > 0 && time.Now().UnixNano() > item.Expiration) {
+		return nil, false
+	}
+	return item.Value, true
+}
+
+func (c *legacyCache) deleteExpired() {
+	now := time.Now().UnixNano()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, item := range c.items {
+		if item.Expiration > 0 && now > item.Expiration {
+			delete(c.items, key)
+		}
+	}
+}
+
+var benchKeys = func() []string {
+	keys := make([]string, 4096)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	return keys
+}()
+
+// BenchmarkCache_Parallel runs 75% reads and 25% writes from every P.
+func BenchmarkCache_Parallel(b *testing.B) {
+	run := func(b *testing.B, set func(string), get func(string)) {
+		for _, k := range benchKeys {
+			set(k)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				k := benchKeys[i&(len(benchKeys)-1)]
+				if i%4 == 0 {
+					set(k)
+				} else {
+					get(k)
+				}
+				i++
+			}
+		})
+	}
+
+	b.Run("legacy", func(b *testing.B) {
+		c := &legacyCache{items: map[string]Item{}}
+		run(b, func(k string) { c.set(k, k, time.Hour) }, func(k string) { c.get(k) })
+	})
+	for _, shards := range []int{1, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			c := NewCache[string, string](WithShards(shards), WithCleanupInterval(0))
+			defer c.StopCleanup()
+			run(b, func(k string) { _ = c.Set(k, k, WithTTL(time.Hour)) }, func(k string) { c.Get(k) })
+		})
+	}
+}
+
+// BenchmarkDeleteExpired measures a cleanup pass over 100k entries of which 1% expired.
+func BenchmarkDeleteExpired(b *testing.B) {
+	const n = 100_000
+	ttl := func(i int) time.Duration {
+		if i%100 == 0 {
+			return -time.Second
+		}
+		return time.Hour
+	}
+
+	b.Run("legacy-scan", func(b *testing.B) {
+		c := &legacyCache{items: map[string]Item{}}
+		for i := 0; i < n; i++ {
+			c.set(strconv.Itoa(i), i, time.Hour)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			for j := 0; j < n; j += 100 {
+				c.set(strconv.Itoa(j), j, ttl(j))
+			}
+			b.StartTimer()
+			c.deleteExpired()
+		}
+	})
+	b.Run("expiry-heap", func(b *testing.B) {
+		c := NewCache[string, int](WithShards(1), WithCleanupInterval(0))
+		for i := 0; i < n; i++ {
+			_ = c.Set(strconv.Itoa(i), i, WithTTL(time.Hour))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			for j := 0; j < n; j += 100 {
+				_ = c.Set(strconv.Itoa(j), j, WithTTL(time.Nanosecond))
+			}
+			time.Sleep(time.Microsecond)
+			b.StartTimer()
+			c.shards[0].deleteExpired()
+		}
+	})
+}
+
+func TestCache_StatsAndOnEvict(t *testing.T) {
+	c := NewCache[string, int](WithMaxEntries(2), WithCleanupInterval(0))
+
+	var mu sync.Mutex
+	reasons := map[string]EvictReason{}
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		mu.Lock()
+		defer mu.Unlock()
+		reasons[key] = reason
+		_ = c.Len() // the callback may use the cache
+	})
+
+	_ = c.Set("a", 1)
+	_ = c.Set("b", 2, WithTTL(time.Millisecond))
+	_ = c.Set("c", 3) // evicts "a"
+	c.Get("c")
+	c.Get("a")
+	time.Sleep(5 * time.Millisecond)
+	c.shards[0].deleteExpired() // expires "b"
+	c.Delete("c")
+
+	want := map[string]EvictReason{"a": EvictCapacity, "b": EvictExpired, "c": EvictDeleted}
+	mu.Lock()
+	for key, reason := range want {
+		if reasons[key] != reason {
+			t.Errorf("Expected %s to be evicted as %s, got %s", key, reason, reasons[key])
+		}
+	}
+	mu.Unlock()
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 1 || stats.Expirations != 1 || stats.Entries != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.HitRatio() != 0.5 {
+		t.Errorf("Expected a hit ratio of 0.5, got %v", stats.HitRatio())
+	}
+}
+
+func TestCache_RedisInvalidationMessage(t *testing.T) {
+	c := NewCache[int, string](WithCleanupInterval(0))
+	tier := newRedisTier[int, string](&redisConfig{prefix: "users"})
+	invalidate := func(key int) { c.shard(key).delete(key, EvictInvalidated) }
+
+	_ = c.Set(7, "stale")
+	tier.handle(`{"origin":"`+tier.origin+`","key":7}`, invalidate)
+	if _, ok := c.Get(7); !ok {
+		t.Errorf("Expected an instance to ignore its own invalidations")
+	}
+	tier.handle(`{"origin":"other-instance","key":7}`, invalidate)
+	if _, ok := c.Get(7); ok {
+		t.Errorf("Expected an invalidation from another instance to drop the local copy")
+	}
+	if tier.key(7) != "users:7" {
+		t.Errorf("Expected Redis key users:7, got %s", tier.key(7))
+	}
+}
+
+func TestCache_TwoTierRedis(t *testing.T) {
+	// Note: This test requires a local Redis instance running on default port.
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("Skipping test because Redis is not reachable at localhost:6379: %v", err)
+	}
+
+	prefix := "localcachew-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	a := NewCache[string, int](WithRedis(client, prefix), WithCleanupInterval(0))
+	b := NewCache[string, int](WithRedis(client, prefix), WithCleanupInterval(0))
+	defer a.StopCleanup()
+	defer b.StopCleanup()
+	time.Sleep(100 * time.Millisecond) // let the subscriptions start
+
+	_ = a.Set("counter", 1, WithTTL(time.Minute))
+	if v, ok := b.Get("counter"); !ok || v != 1 || b.Stats().L2Hits != 1 {
+		t.Fatalf("Expected instance b to read 1 from Redis, got %v (stats %+v)", v, b.Stats())
+	}
+
+	_ = a.Set("counter", 2, WithTTL(time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := b.Get("counter"); v == 2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected instance b to see the new value after the invalidation")
+}
+
+type snapshotProduct struct {
+	Name  string
+	Price int
+}
+
+func TestCache_Snapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	a := NewCache[string, int](WithSnapshot(path), WithCleanupInterval(0))
+	_ = a.Set("forever", 1)
+	_ = a.Set("hour", 2, WithTTL(time.Hour))
+	_ = a.Set("short", 3, WithTTL(20*time.Millisecond))
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatalf("Expected the snapshot to be written, got %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	b := NewCache[string, int](WithSnapshot(path), WithCleanupInterval(0))
+	defer b.StopCleanup()
+	if v, ok := b.Get("forever"); !ok || v != 1 {
+		t.Errorf("Expected forever=1 to be restored, got %v, %v", v, ok)
+	}
+	if _, ok := b.Get("short"); ok || b.Len() != 2 {
+		t.Errorf("Expected the expired entry to be skipped, got %d entries", b.Len())
+	}
+	e, ok := b.shard("hour").lookup("hour")
+	if remaining := time.Until(time.Unix(0, e.expiration)); !ok || remaining <= 0 || remaining > time.Hour {
+		t.Errorf("Expected hour to keep its remaining TTL, got %v", remaining)
+	}
+
+	// Interface values need their concrete types registered.
+	RegisterType(snapshotProduct{})
+	ctx := context.Background()
+	products := New(WithSnapshot(path), WithCleanupInterval(0))
+	_ = products.SetKV(ctx, "p1", snapshotProduct{Name: "Tea", Price: 5})
+	if err := products.Close(ctx); err != nil {
+		t.Fatalf("Expected the snapshot to be written, got %v", err)
+	}
+	restored := New(WithSnapshot(path), WithCleanupInterval(0))
+	defer restored.StopCleanup()
+	if v, err := restored.Get(ctx, "p1"); err != nil || v != (snapshotProduct{Name: "Tea", Price: 5}) {
+		t.Errorf("Expected p1 to be restored, got %v, %v", v, err)
+	}
+
+	// A corrupt snapshot starts the cache empty.
+	_ = os.WriteFile(path, []byte("not gob"), 0o600)
+	if c := NewCache[string, int](WithSnapshot(path), WithCleanupInterval(0)); c.Len() != 0 {
+		t.Errorf("Expected an empty cache from a corrupt snapshot, got %d entries", c.Len())
+	}
+}
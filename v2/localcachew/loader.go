@@ -0,0 +1,119 @@
+package localcachew
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+// Loader loads the value of a key on a cache miss, usually from a database or a remote service.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// flightGroup deduplicates concurrent loads of the same key (singleflight).
+type flightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flight[V]
+}
+
+type flight[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// do runs fn once for every key at a time; concurrent callers for the same key wait for and share its result.
+func (g *flightGroup[K, V]) do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-f.done
+		return f.value, f.err
+	}
+	f := g.start(key)
+	g.mu.Unlock()
+
+	g.run(key, f, fn)
+	return f.value, f.err
+}
+
+// doAsync starts fn in the background unless a load of key is already running.
+func (g *flightGroup[K, V]) doAsync(key K, fn func() (V, error)) {
+	g.mu.Lock()
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return
+	}
+	f := g.start(key)
+	g.mu.Unlock()
+
+	go g.run(key, f, fn)
+}
+
+func (g *flightGroup[K, V]) start(key K) *flight[V] {
+	if g.calls == nil {
+		g.calls = make(map[K]*flight[V])
+	}
+	f := &flight[V]{done: make(chan struct{})}
+	g.calls[key] = f
+	return f
+}
+
+func (g *flightGroup[K, V]) run(key K, f *flight[V], fn func() (V, error)) {
+	defer func() {
+		// A panicking loader must not leave the waiters blocked forever.
+		if r := recover(); r != nil {
+			f.err = fmt.Errorf("localcachew: loader panicked: %v", r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.value, f.err = fn()
+}
+
+// getOrLoad implements Cache.GetOrLoad.
+func (c *Cache[K, V]) getOrLoad(ctx context.Context, key K, loader Loader[K, V], config *SetConfig) (V, error) {
+	if e, found := c.shard(key).lookup(key); found {
+		if e.err != nil {
+			c.state.negativeHits.Add(1)
+			return e.value, e.err
+		}
+		c.state.hits.Add(1)
+		if e.refreshAt > 0 && time.Now().UnixNano() > e.refreshAt {
+			// Serve the stale value while a single background refresh runs. The refresh keeps the
+			// request's values (logger, trace) but not its cancellation.
+			refreshCtx := context.WithoutCancel(ctx)
+			c.flights.doAsync(key, func() (V, error) {
+				value, err := loader(refreshCtx, key)
+				if err != nil {
+					c.shard(key).deferRefresh(key, config.SoftTTL)
+					logw.FromContext(refreshCtx).Named(loggerName).CtxWarningf(refreshCtx,
+						"background refresh of %v failed, serving the stale value: %v", key, err)
+					return value, err
+				}
+				return value, c.set(refreshCtx, key, value, config)
+			})
+		}
+		return e.value, nil
+	}
+
+	return c.flights.do(key, func() (V, error) {
+		if value, found := c.getL2(ctx, key); found {
+			return value, nil
+		}
+		c.state.misses.Add(1)
+
+		value, err := loader(ctx, key)
+		if err != nil {
+			if config.NegativeTTL > 0 {
+				c.shard(key).setError(key, err, config.NegativeTTL)
+			}
+			return value, err
+		}
+		return value, c.set(ctx, key, value, config)
+	})
+}
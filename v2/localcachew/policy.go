@@ -0,0 +1,323 @@
+package localcachew
+
+import (
+	"container/heap"
+	"container/list"
+	"hash/maphash"
+)
+
+// EvictionPolicy selects which entry is dropped when a bounded cache is full.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used entry, the oldest one first on ties.
+	LFU
+	// TinyLFU is W-TinyLFU: new entries enter a small LRU window and only replace entries of the main
+	// segmented LRU when they are estimated to be accessed more often. It resists scans and one-hit wonders.
+	TinyLFU
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case TinyLFU:
+		return "TinyLFU"
+	default:
+		return "unknown"
+	}
+}
+
+// policy tracks the keys of a bounded store. Calls are made with the store lock held.
+type policy[K comparable] interface {
+	add(key K)
+	access(key K)
+	remove(key K)
+	// victim returns the next key to evict; the store removes it and calls remove.
+	victim() (K, bool)
+}
+
+func newPolicy[K comparable](p EvictionPolicy, capacity int, seed maphash.Seed) policy[K] {
+	switch p {
+	case LFU:
+		return newLFUPolicy[K]()
+	case TinyLFU:
+		return newTinyLFUPolicy[K](capacity, seed)
+	default:
+		return newLRUPolicy[K]()
+	}
+}
+
+// lruPolicy keeps keys in recency order, most recent at the front.
+type lruPolicy[K comparable] struct {
+	ll    *list.List
+	elems map[K]*list.Element
+}
+
+func newLRUPolicy[K comparable]() *lruPolicy[K] {
+	return &lruPolicy[K]{ll: list.New(), elems: map[K]*list.Element{}}
+}
+
+func (p *lruPolicy[K]) add(key K) {
+	p.elems[key] = p.ll.PushFront(key)
+}
+
+func (p *lruPolicy[K]) access(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.ll.MoveToFront(e)
+	}
+}
+
+func (p *lruPolicy[K]) remove(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.ll.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lruPolicy[K]) victim() (K, bool) {
+	if e := p.ll.Back(); e != nil {
+		return e.Value.(K), true
+	}
+	var zero K
+	return zero, false
+}
+
+// lfuPolicy keeps keys in a min-heap ordered by access count, then by last access.
+type lfuPolicy[K comparable] struct {
+	h     lfuHeap[K]
+	items map[K]*lfuItem[K]
+	tick  uint64
+}
+
+type lfuItem[K comparable] struct {
+	key   K
+	freq  uint64
+	tick  uint64
+	index int
+}
+
+type lfuHeap[K comparable] []*lfuItem[K]
+
+func (h lfuHeap[K]) Len() int { return len(h) }
+func (h lfuHeap[K]) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].tick < h[j].tick
+}
+func (h lfuHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *lfuHeap[K]) Push(x any) {
+	item := x.(*lfuItem[K])
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *lfuHeap[K]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+func newLFUPolicy[K comparable]() *lfuPolicy[K] {
+	return &lfuPolicy[K]{items: map[K]*lfuItem[K]{}}
+}
+
+func (p *lfuPolicy[K]) add(key K) {
+	p.tick++
+	item := &lfuItem[K]{key: key, freq: 1, tick: p.tick}
+	p.items[key] = item
+	heap.Push(&p.h, item)
+}
+
+func (p *lfuPolicy[K]) access(key K) {
+	if item, ok := p.items[key]; ok {
+		p.tick++
+		item.freq++
+		item.tick = p.tick
+		heap.Fix(&p.h, item.index)
+	}
+}
+
+func (p *lfuPolicy[K]) remove(key K) {
+	if item, ok := p.items[key]; ok {
+		heap.Remove(&p.h, item.index)
+		delete(p.items, key)
+	}
+}
+
+func (p *lfuPolicy[K]) victim() (K, bool) {
+	if len(p.h) > 0 {
+		return p.h[0].key, true
+	}
+	var zero K
+	return zero, false
+}
+
+// Segments of the W-TinyLFU policy.
+const (
+	segWindow = iota
+	segProbation
+	segProtected
+)
+
+// tinyLFUPolicy implements W-TinyLFU: a window LRU holding about 1% of the entries in front of a
+// segmented LRU (20% probation, 80% protected), with admission decided by a count-min sketch.
+type tinyLFUPolicy[K comparable] struct {
+	seed     maphash.Seed
+	sketch   *countMinSketch
+	segments [3]*list.List
+	elems    map[K]*list.Element
+}
+
+type tinyItem[K comparable] struct {
+	key K
+	seg int
+	// candidate marks an entry that left the window and has not been accessed in probation yet.
+	candidate bool
+}
+
+func newTinyLFUPolicy[K comparable](capacity int, seed maphash.Seed) *tinyLFUPolicy[K] {
+	p := &tinyLFUPolicy[K]{seed: seed, sketch: newCountMinSketch(capacity), elems: map[K]*list.Element{}}
+	for i := range p.segments {
+		p.segments[i] = list.New()
+	}
+	return p
+}
+
+func (p *tinyLFUPolicy[K]) hash(key K) uint64 {
+	return maphash.Comparable(p.seed, key)
+}
+
+func (p *tinyLFUPolicy[K]) add(key K) {
+	p.sketch.increment(p.hash(key))
+	p.elems[key] = p.segments[segWindow].PushFront(&tinyItem[K]{key: key, seg: segWindow})
+
+	// Entries leaving the window become admission candidates at the front of probation.
+	window := p.segments[segWindow]
+	for window.Len() > max(1, len(p.elems)/100) {
+		e := window.Back()
+		e.Value.(*tinyItem[K]).candidate = true
+		p.move(e, segProbation)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) access(key K) {
+	p.sketch.increment(p.hash(key))
+	e, ok := p.elems[key]
+	if !ok {
+		return
+	}
+	item := e.Value.(*tinyItem[K])
+	item.candidate = false
+	switch item.seg {
+	case segWindow, segProtected:
+		p.segments[item.seg].MoveToFront(e)
+	case segProbation:
+		// A second hit promotes the entry; the protected segment stays within 80% of the main space.
+		p.move(e, segProtected)
+		main := p.segments[segProbation].Len() + p.segments[segProtected].Len()
+		if protected := p.segments[segProtected]; protected.Len() > max(1, main*8/10) {
+			p.move(protected.Back(), segProbation)
+		}
+	}
+}
+
+func (p *tinyLFUPolicy[K]) move(e *list.Element, seg int) {
+	item := e.Value.(*tinyItem[K])
+	p.segments[item.seg].Remove(e)
+	item.seg = seg
+	p.elems[item.key] = p.segments[seg].PushFront(item)
+}
+
+func (p *tinyLFUPolicy[K]) remove(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.segments[e.Value.(*tinyItem[K]).seg].Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) victim() (K, bool) {
+	probation := p.segments[segProbation]
+	if front, back := probation.Front(), probation.Back(); front != nil && front != back {
+		// The newest candidate competes with the oldest probation entry,
+		// and the one with the lower estimated frequency is evicted.
+		candidate, victim := front.Value.(*tinyItem[K]), back.Value.(*tinyItem[K])
+		if candidate.candidate && p.sketch.estimate(p.hash(candidate.key)) <= p.sketch.estimate(p.hash(victim.key)) {
+			return candidate.key, true
+		}
+		candidate.candidate = false // admitted
+		return victim.key, true
+	}
+
+	for _, seg := range []int{segProbation, segProtected, segWindow} {
+		if e := p.segments[seg].Back(); e != nil {
+			return e.Value.(*tinyItem[K]).key, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+// countMinSketch estimates access frequencies with 4 rows of saturating 4-bit counters.
+// Every counter is halved once the number of increments reaches ten times the width,
+// so old popularity fades.
+type countMinSketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 1024
+	for width < capacity {
+		width <<= 1
+	}
+	s := &countMinSketch{mask: uint64(width - 1), resetAt: width * 10}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *countMinSketch) index(h uint64, row int) uint64 {
+	h += uint64(row+1) * 0x9E3779B97F4A7C15
+	h ^= h >> 31
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 29
+	return h & s.mask
+}
+
+func (s *countMinSketch) increment(h uint64) {
+	for i := range s.rows {
+		if c := &s.rows[i][s.index(h, i)]; *c < 15 {
+			*c++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] >>= 1
+			}
+		}
+		s.additions /= 2
+	}
+}
+
+func (s *countMinSketch) estimate(h uint64) uint8 {
+	est := uint8(15)
+	for i := range s.rows {
+		est = min(est, s.rows[i][s.index(h, i)])
+	}
+	return est
+}
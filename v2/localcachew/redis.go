@@ -0,0 +1,161 @@
+package localcachew
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds the Redis calls of two-tier methods that take no context, such as Cache.Get.
+const redisTimeout = time.Second
+
+// redisConfig holds the settings of WithRedis.
+type redisConfig struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// WithRedis enables the two-tier mode: local misses fall through to Redis (for example a client from
+// redisw.Connect), writes go to both tiers, and every Set or Delete is broadcast on the Redis channel
+// "<prefix>:invalidate" so other instances drop their local copy. Values are stored as JSON under
+// "<prefix>:<key>", so use a Cache with a concrete value type for values to round-trip.
+//
+// Example:
+//
+//	client, _ := redisw.Connect(ctx, &redisw.Config{Address: "localhost:6379"})
+//	products := localcachew.NewCache[string, Product](
+//		localcachew.WithMaxEntries(10_000),
+//		localcachew.WithRedis(client, "products"),
+//	)
+func WithRedis(client redis.UniversalClient, prefix string) CacheOption {
+	return func(c *cacheConfig) {
+		c.redis = &redisConfig{client: client, prefix: prefix}
+	}
+}
+
+// redisTier is the shared second tier of a cache.
+type redisTier[K comparable, V any] struct {
+	client  redis.UniversalClient
+	prefix  string
+	channel string
+	origin  string // origin identifies this instance, so it ignores its own invalidations
+	pubsub  *redis.PubSub
+	done    chan struct{}
+}
+
+// invalidation is the message broadcast on the invalidation channel.
+type invalidation struct {
+	Origin string          `json:"origin"`
+	Key    json.RawMessage `json:"key"`
+}
+
+func newRedisTier[K comparable, V any](cfg *redisConfig) *redisTier[K, V] {
+	return &redisTier[K, V]{
+		client:  cfg.client,
+		prefix:  cfg.prefix,
+		channel: cfg.prefix + ":invalidate",
+		origin:  uuid.NewString(),
+		done:    make(chan struct{}),
+	}
+}
+
+func (t *redisTier[K, V]) key(key K) string {
+	return fmt.Sprintf("%s:%v", t.prefix, key)
+}
+
+// get returns the value of key and its remaining TTL (0 if it never expires).
+func (t *redisTier[K, V]) get(ctx context.Context, key K) (V, time.Duration, bool, error) {
+	var value V
+	pipe := t.client.Pipeline()
+	getCmd := pipe.Get(ctx, t.key(key))
+	ttlCmd := pipe.PTTL(ctx, t.key(key))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return value, 0, false, fmt.Errorf("localcachew: failed to read %s from redis: %w", t.key(key), err)
+	}
+
+	data, err := getCmd.Bytes()
+	if errors.Is(err, redis.Nil) {
+		return value, 0, false, nil
+	}
+	if err != nil {
+		return value, 0, false, fmt.Errorf("localcachew: failed to read %s from redis: %w", t.key(key), err)
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, 0, false, fmt.Errorf("localcachew: failed to decode %s from redis: %w", t.key(key), err)
+	}
+	return value, max(ttlCmd.Val(), 0), true, nil
+}
+
+func (t *redisTier[K, V]) set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("localcachew: failed to encode %s for redis: %w", t.key(key), err)
+	}
+	if err := t.client.Set(ctx, t.key(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("localcachew: failed to write %s to redis: %w", t.key(key), err)
+	}
+	return t.publish(ctx, key)
+}
+
+func (t *redisTier[K, V]) del(ctx context.Context, key K) error {
+	if err := t.client.Del(ctx, t.key(key)).Err(); err != nil {
+		return fmt.Errorf("localcachew: failed to delete %s from redis: %w", t.key(key), err)
+	}
+	return t.publish(ctx, key)
+}
+
+func (t *redisTier[K, V]) publish(ctx context.Context, key K) error {
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("localcachew: failed to encode key %v: %w", key, err)
+	}
+	msg, _ := json.Marshal(invalidation{Origin: t.origin, Key: encodedKey})
+	if err := t.client.Publish(ctx, t.channel, msg).Err(); err != nil {
+		return fmt.Errorf("localcachew: failed to publish invalidation of %s: %w", t.key(key), err)
+	}
+	return nil
+}
+
+// listen subscribes to the invalidation channel and calls invalidate for keys changed by other instances.
+func (t *redisTier[K, V]) listen(invalidate func(key K)) {
+	t.pubsub = t.client.Subscribe(context.Background(), t.channel)
+	go func() {
+		defer close(t.done)
+		for msg := range t.pubsub.Channel() {
+			t.handle(msg.Payload, invalidate)
+		}
+	}()
+}
+
+func (t *redisTier[K, V]) handle(payload string, invalidate func(key K)) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		logw.Named(loggerName).Warningf("ignoring malformed invalidation on %s: %v", t.channel, err)
+		return
+	}
+	if msg.Origin == t.origin {
+		return
+	}
+	var key K
+	if err := json.Unmarshal(msg.Key, &key); err != nil {
+		logw.Named(loggerName).Warningf("ignoring invalidation with a malformed key on %s: %v", t.channel, err)
+		return
+	}
+	invalidate(key)
+}
+
+func (t *redisTier[K, V]) close() {
+	if t.pubsub == nil {
+		return
+	}
+	if err := t.pubsub.Close(); err != nil {
+		logw.Named(loggerName).Warningf("failed to close the subscription to %s: %v", t.channel, err)
+	}
+	<-t.done
+}
@@ -0,0 +1,126 @@
+package localcachew
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+// WithSnapshot persists the cache to path: unexpired entries are restored when the cache is created
+// (or the global cache is initialized with Init) and written back by StopCleanup or Close.
+// Entries keep their original expiration time, so the time spent down counts against their TTL and
+// entries that expired meanwhile are skipped. The file is encoded with encoding/gob; concrete types
+// stored behind an interface (e.g. in the global cache) must be registered with RegisterType.
+//
+// Example:
+//
+//	localcachew.RegisterType(Product{})
+//	localcachew.Init(time.Minute, localcachew.WithSnapshot("/var/cache/app/localcache.gob"))
+//	gracefulw.Register("LocalCache", localcachew.Close) // writes the snapshot
+func WithSnapshot(path string) CacheOption {
+	return func(c *cacheConfig) {
+		c.snapshotPath = path
+	}
+}
+
+// RegisterType registers the concrete type of value for snapshots of caches whose values are interfaces,
+// such as the global cache and caches created with New.
+func RegisterType(value any) {
+	gob.Register(value)
+}
+
+// snapshotEntry is the persisted form of an entry.
+type snapshotEntry[K comparable, V any] struct {
+	Key        K
+	Value      V
+	Expiration int64 // Unix timestamp in nanoseconds. 0 means it never expires.
+	RefreshAt  int64
+	Cost       int64
+}
+
+// SaveSnapshot writes every unexpired entry to path, replacing the file atomically.
+func (c *Cache[K, V]) SaveSnapshot(path string) error {
+	now := time.Now().UnixNano()
+	var entries []snapshotEntry[K, V]
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for _, e := range s.items {
+			if e.err == nil && !e.expired(now) {
+				entries = append(entries, snapshotEntry[K, V]{
+					Key: e.key, Value: e.value, Expiration: e.expiration, RefreshAt: e.refreshAt, Cost: e.cost,
+				})
+			}
+		}
+		s.mu.RUnlock()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("localcachew: failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(entries); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("localcachew: failed to encode snapshot (register interface values with RegisterType): %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("localcachew: failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("localcachew: failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot restores the entries saved in path and returns how many were restored.
+// Entries that expired since the snapshot was taken are skipped. A missing file restores nothing.
+func (c *Cache[K, V]) LoadSnapshot(path string) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("localcachew: failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	var entries []snapshotEntry[K, V]
+	if err := gob.NewDecoder(f).Decode(&entries); err != nil {
+		return 0, fmt.Errorf("localcachew: failed to decode snapshot %s: %w", path, err)
+	}
+
+	now := time.Now().UnixNano()
+	restored := 0
+	for _, se := range entries {
+		e := &entry[K, V]{
+			key: se.Key, value: se.Value, expiration: se.Expiration, refreshAt: se.RefreshAt, cost: se.Cost, heapIndex: -1,
+		}
+		if e.expired(now) {
+			continue
+		}
+		s := c.shard(se.Key)
+		s.mu.Lock()
+		s.putLocked(e)
+		s.unlock()
+		restored++
+	}
+	return restored, nil
+}
+
+// restoreSnapshot warm-starts a new cache; a broken snapshot is logged and the cache starts cold.
+func (c *Cache[K, V]) restoreSnapshot() {
+	n, err := c.LoadSnapshot(c.snapshotPath)
+	if err != nil {
+		logw.Named(loggerName).Warningf("starting with an empty cache: %v", err)
+		return
+	}
+	if n > 0 {
+		logw.Named(loggerName).Infof("Restored %d cache entries from %s", n, c.snapshotPath)
+	}
+}
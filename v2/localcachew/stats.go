@@ -0,0 +1,67 @@
+package localcachew
+
+import "sync/atomic"
+
+// EvictReason tells an OnEvict callback why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity means the eviction policy dropped the entry to respect WithMaxEntries or WithMaxCost.
+	EvictCapacity EvictReason = iota + 1
+	// EvictExpired means the background cleanup removed the entry after its TTL.
+	EvictExpired
+	// EvictDeleted means the entry was removed by Delete or Clear.
+	EvictDeleted
+	// EvictInvalidated means another instance changed the key in two-tier mode (WithRedis).
+	EvictInvalidated
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictInvalidated:
+		return "invalidated"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheStats is a snapshot of the counters of a cache since it was created.
+type CacheStats struct {
+	Hits         uint64 // Hits counts lookups served from local memory.
+	Misses       uint64 // Misses counts lookups found in neither tier.
+	L2Hits       uint64 // L2Hits counts local misses served from Redis in two-tier mode.
+	NegativeHits uint64 // NegativeHits counts GetOrLoad calls answered with a cached loader error.
+	Evictions    uint64 // Evictions counts entries dropped by the eviction policy.
+	Expirations  uint64 // Expirations counts expired entries removed by the background cleanup.
+	Entries      int    // Entries is the current number of local entries.
+}
+
+// HitRatio returns the share of lookups served with a value by either tier, between 0 and 1.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.L2Hits + s.Misses + s.NegativeHits
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.L2Hits) / float64(total)
+}
+
+// cacheState holds the counters and the eviction callback shared by the shards of a cache.
+type cacheState[K comparable, V any] struct {
+	hits, misses, l2Hits   atomic.Uint64
+	negativeHits           atomic.Uint64
+	evictions, expirations atomic.Uint64
+	onEvict                atomic.Pointer[func(key K, value V, reason EvictReason)]
+}
+
+// evicted is an entry removed under a shard lock, reported to OnEvict once the lock is released.
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
@@ -0,0 +1,302 @@
+package localcachew
+
+import (
+	"container/heap"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// maxRefreshBackoff caps the doubling of the delay between failed background refreshes at 2^6 soft TTLs.
+const maxRefreshBackoff = 6
+
+// expireBatch bounds how many expired entries deleteExpired removes per lock acquisition,
+// so readers and writers of a shard are never blocked for long.
+const expireBatch = 256
+
+// entry is a cached value together with its expiration and cost.
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	expiration int64 // Unix timestamp in nanoseconds. 0 means it never expires.
+	refreshAt  int64 // Unix timestamp in nanoseconds after which GetOrLoad refreshes the value. 0 disables it.
+	err        error // err is a cached loader error (negative caching); such entries hold no value.
+	cost       int64
+	heapIndex  int // position in the expiry heap, -1 when the entry never expires
+
+	refreshFailures int // consecutive failed background refreshes, reset when the value is replaced
+}
+
+func (e *entry[K, V]) expired(now int64) bool {
+	return e.expiration > 0 && now > e.expiration
+}
+
+// expiryHeap is a min-heap of the expiring entries of a store, soonest expiration first.
+type expiryHeap[K comparable, V any] []*entry[K, V]
+
+func (h expiryHeap[K, V]) Len() int           { return len(h) }
+func (h expiryHeap[K, V]) Less(i, j int) bool { return h[i].expiration < h[j].expiration }
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex, h[j].heapIndex = i, j
+}
+func (h *expiryHeap[K, V]) Push(x any) {
+	e := x.(*entry[K, V])
+	e.heapIndex = len(*h)
+	*h = append(*h, e)
+}
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.heapIndex = -1
+	*h = old[:len(old)-1]
+	return e
+}
+
+// store is a map guarded by a single lock, optionally bounded by entry count and total cost.
+// A Cache is made of one or more stores (shards).
+type store[K comparable, V any] struct {
+	mu         sync.RWMutex
+	items      map[K]*entry[K, V]
+	expiries   expiryHeap[K, V]
+	policy     policy[K] // nil for unbounded stores, which never evict
+	maxEntries int
+	maxCost    int64
+	cost       int64
+	sizeFn     func(K, V) int64
+	state      *cacheState[K, V]
+	pending    []evicted[K, V] // removed entries waiting for the OnEvict callback
+}
+
+func newStore[K comparable, V any](cfg *cacheConfig, maxEntries int, maxCost int64, sizeFn func(K, V) int64,
+	seed maphash.Seed, state *cacheState[K, V]) *store[K, V] {
+	s := &store[K, V]{
+		state:      state,
+		items:      make(map[K]*entry[K, V]),
+		maxEntries: maxEntries,
+		maxCost:    maxCost,
+		sizeFn:     sizeFn,
+	}
+	if s.maxEntries > 0 || s.maxCost > 0 {
+		s.policy = newPolicy[K](cfg.policy, s.maxEntries, seed)
+	}
+	return s
+}
+
+func (s *store[K, V]) set(key K, value V, config *SetConfig) error {
+	now := time.Now()
+	e := &entry[K, V]{key: key, value: value, heapIndex: -1}
+	if config.TTL > 0 {
+		e.expiration = now.Add(config.TTL).UnixNano()
+	}
+	if config.SoftTTL > 0 {
+		e.refreshAt = now.Add(config.SoftTTL).UnixNano()
+	}
+
+	cost := config.Cost
+	if cost <= 0 && s.sizeFn != nil {
+		cost = s.sizeFn(key, value)
+	}
+	if cost <= 0 {
+		cost = 1
+	}
+	if s.maxCost > 0 && cost > s.maxCost {
+		return fmt.Errorf("%w: cost %d exceeds the max cost %d", ErrCostTooHigh, cost, s.maxCost)
+	}
+	e.cost = cost
+
+	s.mu.Lock()
+	defer s.unlock()
+	s.putLocked(e)
+	return nil
+}
+
+// setError caches a loader error for ttl, so GetOrLoad returns it without calling the loader again.
+func (s *store[K, V]) setError(key K, err error, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.unlock()
+	s.putLocked(&entry[K, V]{key: key, err: err, expiration: time.Now().Add(ttl).UnixNano(), cost: 1, heapIndex: -1})
+}
+
+func (s *store[K, V]) putLocked(e *entry[K, V]) {
+	if old, found := s.items[e.key]; found {
+		s.cost -= old.cost
+		if old.heapIndex >= 0 {
+			heap.Remove(&s.expiries, old.heapIndex)
+		}
+		if s.policy != nil {
+			s.policy.access(e.key)
+		}
+	} else if s.policy != nil {
+		s.policy.add(e.key)
+	}
+	s.items[e.key] = e
+	s.cost += e.cost
+	if e.expiration > 0 {
+		heap.Push(&s.expiries, e)
+	}
+
+	for s.overLimit() {
+		victim, ok := s.policy.victim()
+		if !ok {
+			break
+		}
+		s.removeLocked(victim, EvictCapacity)
+	}
+}
+
+func (s *store[K, V]) overLimit() bool {
+	return (s.maxEntries > 0 && len(s.items) > s.maxEntries) || (s.maxCost > 0 && s.cost > s.maxCost)
+}
+
+// get returns the value of key if it exists and has not expired (lazy eviction).
+func (s *store[K, V]) get(key K) (V, bool) {
+	e, found := s.lookup(key)
+	if !found || e.err != nil {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// lookup returns a copy of the unexpired entry of key, including cached loader errors.
+// Bounded stores take the write lock because a hit updates the eviction policy.
+func (s *store[K, V]) lookup(key K) (entry[K, V], bool) {
+	if s.policy == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	e, found := s.items[key]
+	if !found || e.expired(time.Now().UnixNano()) {
+		return entry[K, V]{}, false
+	}
+	if s.policy != nil {
+		s.policy.access(key)
+	}
+	return *e, true
+}
+
+// deferRefresh pushes back the next background refresh of key after a failed one, doubling the delay
+// with every consecutive failure, so a failing backend is not hit by every later GetOrLoad.
+func (s *store[K, V]) deferRefresh(key K, softTTL time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, found := s.items[key]
+	// An entry replaced since the refresh started is not due anymore and keeps its schedule.
+	if !found || e.err != nil || e.refreshAt == 0 || e.refreshAt > now.UnixNano() {
+		return
+	}
+	if softTTL <= 0 {
+		softTTL = time.Second
+	}
+	delay := softTTL << min(e.refreshFailures, maxRefreshBackoff)
+	e.refreshFailures++
+	e.refreshAt = now.Add(delay).UnixNano()
+}
+
+func (s *store[K, V]) delete(key K, reason EvictReason) {
+	s.mu.Lock()
+	defer s.unlock()
+	s.removeLocked(key, reason)
+}
+
+func (s *store[K, V]) removeLocked(key K, reason EvictReason) {
+	e, found := s.items[key]
+	if !found {
+		return
+	}
+	delete(s.items, key)
+	s.cost -= e.cost
+	if e.heapIndex >= 0 {
+		heap.Remove(&s.expiries, e.heapIndex)
+	}
+	if s.policy != nil {
+		s.policy.remove(key)
+	}
+
+	switch reason {
+	case EvictCapacity:
+		s.state.evictions.Add(1)
+	case EvictExpired:
+		s.state.expirations.Add(1)
+	}
+	if e.err == nil && s.state.onEvict.Load() != nil {
+		s.pending = append(s.pending, evicted[K, V]{key: key, value: e.value, reason: reason})
+	}
+}
+
+// unlock releases the write lock, then reports the entries removed while it was held to OnEvict,
+// so the callback may use the cache.
+func (s *store[K, V]) unlock() {
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	if fn := s.state.onEvict.Load(); fn != nil {
+		for _, ev := range pending {
+			(*fn)(ev.key, ev.value, ev.reason)
+		}
+	}
+}
+
+// snapshot returns the unexpired entries as parallel key and value slices.
+func (s *store[K, V]) snapshot() ([]K, []V) {
+	now := time.Now().UnixNano()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]K, 0, len(s.items))
+	values := make([]V, 0, len(s.items))
+	for key, e := range s.items {
+		if !e.expired(now) && e.err == nil {
+			keys = append(keys, key)
+			values = append(values, e.value)
+		}
+	}
+	return keys, values
+}
+
+func (s *store[K, V]) len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
+
+func (s *store[K, V]) clear() {
+	s.mu.Lock()
+	defer s.unlock()
+	for key := range s.items {
+		s.removeLocked(key, EvictDeleted)
+	}
+}
+
+// deleteExpired pops expired entries off the expiry heap in batches of expireBatch,
+// releasing the lock between batches instead of scanning the whole map under it.
+func (s *store[K, V]) deleteExpired() {
+	now := time.Now().UnixNano()
+	for {
+		s.mu.Lock()
+		n := 0
+		for n < expireBatch && len(s.expiries) > 0 && s.expiries[0].expired(now) {
+			s.removeLocked(s.expiries[0].key, EvictExpired)
+			n++
+		}
+		s.unlock()
+
+		if n < expireBatch {
+			return
+		}
+	}
+}
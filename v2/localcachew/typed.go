@@ -0,0 +1,321 @@
+package localcachew
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+// Cache is a typed cache instance. It accepts the same CacheOption settings as New
+// and the same per-item Option settings (WithTTL, WithCost) as SetKV.
+//
+// Entries are spread over shards by key hash, each with its own lock, eviction policy and expiry heap.
+type Cache[K comparable, V any] struct {
+	shards       []*store[K, V]
+	seed         maphash.Seed
+	mask         uint64
+	state        *cacheState[K, V]
+	l2           *redisTier[K, V] // nil unless WithRedis is set
+	flights      flightGroup[K, V]
+	cleanupFreq  time.Duration
+	stopCleanup  chan struct{}
+	stopOnce     sync.Once
+	stopErr      error
+	snapshotPath string // empty unless WithSnapshot is set
+}
+
+// NewCache creates a typed cache instance. Without WithMaxEntries or WithMaxCost it is unbounded.
+//
+// Example:
+//
+//	users := localcachew.NewCache[int64, *User](localcachew.WithMaxEntries(50_000))
+//	users.Set(u.ID, u, localcachew.WithTTL(10*time.Minute))
+//	if u, ok := users.Get(id); ok { ... }
+func NewCache[K comparable, V any](opts ...CacheOption) *Cache[K, V] {
+	// WithSizeFunc only fits a Cache[string, any], which is what New builds.
+	sizeFn, _ := any(newCacheConfig(opts).sizeFn).(func(K, V) int64)
+	return NewSizedCache(sizeFn, opts...)
+}
+
+// NewSizedCache creates a typed cache whose entries cost sizeFn(key, value), e.g. their size in bytes,
+// unless Set is given WithCost. Bound the total cost with WithMaxCost.
+//
+// Example:
+//
+//	blobs := localcachew.NewSizedCache(func(key string, b []byte) int64 { return int64(len(b)) },
+//		localcachew.WithMaxCost(64<<20),
+//	)
+func NewSizedCache[K comparable, V any](sizeFn func(key K, value V) int64, opts ...CacheOption) *Cache[K, V] {
+	cfg := newCacheConfig(opts)
+	n := cfg.shardCount()
+	c := &Cache[K, V]{
+		shards:       make([]*store[K, V], n),
+		seed:         maphash.MakeSeed(),
+		mask:         uint64(n - 1),
+		state:        &cacheState[K, V]{},
+		cleanupFreq:  cfg.cleanupInterval,
+		stopCleanup:  make(chan struct{}),
+		snapshotPath: cfg.snapshotPath,
+	}
+	// Bounds are split evenly, so a sharded cache evicts per shard rather than globally.
+	maxEntries := (cfg.maxEntries + n - 1) / n
+	maxCost := (cfg.maxCost + int64(n) - 1) / int64(n)
+	for i := range c.shards {
+		c.shards[i] = newStore(cfg, maxEntries, maxCost, sizeFn, c.seed, c.state)
+	}
+	if c.snapshotPath != "" {
+		c.restoreSnapshot()
+	}
+	if cfg.redis != nil {
+		c.l2 = newRedisTier[K, V](cfg.redis)
+		c.l2.listen(func(key K) {
+			c.shard(key).delete(key, EvictInvalidated)
+		})
+	}
+	if c.cleanupFreq > 0 {
+		go c.startBackgroundCleanup()
+	}
+	return c
+}
+
+func (c *Cache[K, V]) startBackgroundCleanup() {
+	ticker := time.NewTicker(c.cleanupFreq)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, s := range c.shards {
+				s.deleteExpired()
+			}
+		case <-c.stopCleanup:
+			logw.Named(loggerName).Info("Local cache background cleanup stopped gracefully.")
+			return
+		}
+	}
+}
+
+func (c *Cache[K, V]) shard(key K) *store[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[maphash.Comparable(c.seed, key)&c.mask]
+}
+
+// Get returns the value of key, or false if it is missing or expired.
+// In two-tier mode, a local miss is looked up in Redis and copied into local memory.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	ctx, cancel := c.redisContext(context.Background())
+	defer cancel()
+	return c.get(ctx, key)
+}
+
+// Set inserts or updates an item. If the cache is bounded, other entries may be evicted.
+// In two-tier mode, the item is also written to Redis and other instances drop their local copy.
+func (c *Cache[K, V]) Set(key K, value V, opts ...Option) error {
+	config := &SetConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	ctx, cancel := c.redisContext(context.Background())
+	defer cancel()
+	return c.set(ctx, key, value, config)
+}
+
+// redisContext bounds the Redis calls made on behalf of methods without a caller deadline.
+func (c *Cache[K, V]) redisContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.l2 == nil {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, redisTimeout)
+}
+
+func (c *Cache[K, V]) get(ctx context.Context, key K) (V, bool) {
+	if value, found := c.shard(key).get(key); found {
+		c.state.hits.Add(1)
+		return value, true
+	}
+	if value, found := c.getL2(ctx, key); found {
+		return value, true
+	}
+	c.state.misses.Add(1)
+	return value0[V](), false
+}
+
+// getL2 looks key up in Redis and copies a hit into local memory with its remaining TTL.
+func (c *Cache[K, V]) getL2(ctx context.Context, key K) (V, bool) {
+	if c.l2 == nil {
+		return value0[V](), false
+	}
+	value, ttl, found, err := c.l2.get(ctx, key)
+	if err != nil {
+		logw.FromContext(ctx).Named(loggerName).CtxWarningf(ctx, "falling back to a miss: %v", err)
+		return value, false
+	}
+	if !found {
+		return value, false
+	}
+	c.state.l2Hits.Add(1)
+	_ = c.shard(key).set(key, value, &SetConfig{TTL: ttl})
+	return value, true
+}
+
+func (c *Cache[K, V]) set(ctx context.Context, key K, value V, config *SetConfig) error {
+	if err := c.shard(key).set(key, value, config); err != nil {
+		return err
+	}
+	if c.l2 != nil {
+		return c.l2.set(ctx, key, value, config.TTL)
+	}
+	return nil
+}
+
+func (c *Cache[K, V]) delete(ctx context.Context, key K) error {
+	c.shard(key).delete(key, EvictDeleted)
+	if c.l2 != nil {
+		return c.l2.del(ctx, key)
+	}
+	return nil
+}
+
+func value0[V any]() V {
+	var zero V
+	return zero
+}
+
+// GetOrLoad returns the cached value of key, or calls loader and caches its result with opts.
+// Concurrent calls for the same key share a single loader call, which runs with the first caller's ctx.
+//
+// With WithSoftTTL, a value older than the soft TTL is still returned but refreshed by a single
+// background load; a failed refresh keeps the stale value until its hard TTL (WithTTL) and is retried
+// one soft TTL later, doubling the wait after every consecutive failure.
+// With WithNegativeTTL, loader errors are cached and returned for that long; otherwise nothing is cached.
+//
+// Example:
+//
+//	user, err := users.GetOrLoad(ctx, id, repo.FindUser,
+//		localcachew.WithTTL(10*time.Minute),
+//		localcachew.WithSoftTTL(time.Minute),
+//		localcachew.WithNegativeTTL(5*time.Second),
+//	)
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V], opts ...Option) (V, error) {
+	config := &SetConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return c.getOrLoad(ctx, key, loader, config)
+}
+
+// Delete removes key. In two-tier mode it is also removed from Redis and from the other instances;
+// Redis failures are logged.
+func (c *Cache[K, V]) Delete(key K) {
+	ctx, cancel := c.redisContext(context.Background())
+	defer cancel()
+	if err := c.delete(ctx, key); err != nil {
+		logw.Named(loggerName).Warningf("failed to delete %v from the shared tier: %v", key, err)
+	}
+}
+
+// Range calls fn for every unexpired entry until fn returns false. It iterates over a snapshot,
+// so fn may modify the cache.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	for _, s := range c.shards {
+		keys, values := s.snapshot()
+		for i, key := range keys {
+			if !fn(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the keys of every unexpired entry, in no particular order.
+func (c *Cache[K, V]) Keys() []K {
+	var keys []K
+	for _, s := range c.shards {
+		shardKeys, _ := s.snapshot()
+		keys = append(keys, shardKeys...)
+	}
+	return keys
+}
+
+// Len returns the number of entries, including expired ones not yet cleaned up.
+func (c *Cache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.len()
+	}
+	return n
+}
+
+// Clear removes every local entry. In two-tier mode, Redis is left untouched.
+func (c *Cache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.clear()
+	}
+}
+
+// Stats returns the counters of the cache since it was created.
+func (c *Cache[K, V]) Stats() CacheStats {
+	return CacheStats{
+		Hits:         c.state.hits.Load(),
+		Misses:       c.state.misses.Load(),
+		NegativeHits: c.state.negativeHits.Load(),
+		L2Hits:       c.state.l2Hits.Load(),
+		Evictions:    c.state.evictions.Load(),
+		Expirations:  c.state.expirations.Load(),
+		Entries:      c.Len(),
+	}
+}
+
+// OnEvict registers fn to be called for every entry removed from local memory, with the reason.
+// It replaces any previous callback. fn runs after the shard lock is released, so it may use the cache,
+// but it should be quick because it runs on the goroutine that caused the removal.
+//
+// Example:
+//
+//	cache.OnEvict(func(key string, value *Session, reason localcachew.EvictReason) {
+//		metrics.CacheEvictions.WithLabelValues(reason.String()).Inc()
+//	})
+func (c *Cache[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	if fn == nil {
+		c.state.onEvict.Store(nil)
+		return
+	}
+	c.state.onEvict.Store(&fn)
+}
+
+// StopCleanup halts the background garbage collector and, in two-tier mode, the invalidation
+// subscriber. With WithSnapshot, it then writes the snapshot; failures are logged.
+// It is safe to call more than once.
+func (c *Cache[K, V]) StopCleanup() {
+	if err := c.stop(); err != nil {
+		logw.Named(loggerName).Errorf("%v", err)
+	}
+}
+
+// Close is StopCleanup returning the snapshot error instead of logging it.
+// Its signature matches gracefulw.CleanupFunc.
+//
+// Example:
+//
+//	gracefulw.Register("ProductCache", products.Close)
+func (c *Cache[K, V]) Close(_ context.Context) error {
+	return c.stop()
+}
+
+func (c *Cache[K, V]) stop() error {
+	c.stopOnce.Do(func() {
+		close(c.stopCleanup)
+		if c.l2 != nil {
+			c.l2.close()
+		}
+		if c.snapshotPath != "" {
+			c.stopErr = c.SaveSnapshot(c.snapshotPath)
+		}
+	})
+	return c.stopErr
+}
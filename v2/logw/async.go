@@ -0,0 +1,165 @@
+package logw
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrWriterClosed is returned when writing to an AsyncWriter or BrokerWriter that has been closed.
+var ErrWriterClosed = errors.New("logw: writer is closed")
+
+// OverflowPolicy decides what an AsyncWriter does when its buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock makes the caller wait until the buffer has room (no log loss).
+	OverflowBlock OverflowPolicy = "BLOCK"
+	// OverflowDrop discards the entry and increments the dropped counter (callers never wait).
+	OverflowDrop OverflowPolicy = "DROP"
+)
+
+const defaultAsyncBufferSize = 1024
+
+// AsyncWriter decouples log producers from a slow destination (file, broker).
+// Entries are copied into a bounded ring buffer and written by a single background goroutine.
+type AsyncWriter struct {
+	out    io.Writer
+	policy OverflowPolicy
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	ring    [][]byte
+	head    int
+	count   int
+	writing bool
+	closed  bool
+
+	dropped atomic.Uint64
+	done    chan struct{}
+}
+
+// NewAsyncWriter starts an AsyncWriter in front of out.
+// size is the number of buffered entries (defaults to 1024); policy defaults to OverflowBlock.
+func NewAsyncWriter(out io.Writer, size int, policy OverflowPolicy) *AsyncWriter {
+	if size <= 0 {
+		size = defaultAsyncBufferSize
+	}
+	if policy == "" {
+		policy = OverflowBlock
+	}
+
+	w := &AsyncWriter{
+		out:    out,
+		policy: policy,
+		ring:   make([][]byte, size),
+		done:   make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	go w.loop()
+	return w
+}
+
+// Write enqueues a copy of p. It never performs I/O on the caller's goroutine.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	// slog reuses its buffers, so the entry must be copied before returning.
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for !w.closed && w.count == len(w.ring) {
+		if w.policy == OverflowDrop {
+			w.dropped.Add(1)
+			return len(p), nil
+		}
+		w.cond.Wait()
+	}
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	w.ring[(w.head+w.count)%len(w.ring)] = entry
+	w.count++
+	w.cond.Broadcast()
+	return len(p), nil
+}
+
+func (w *AsyncWriter) loop() {
+	defer close(w.done)
+
+	for {
+		w.mu.Lock()
+		for w.count == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if w.count == 0 && w.closed {
+			w.mu.Unlock()
+			return
+		}
+
+		entry := w.ring[w.head]
+		w.ring[w.head] = nil
+		w.head = (w.head + 1) % len(w.ring)
+		w.count--
+		w.writing = true
+		w.mu.Unlock()
+
+		_, _ = w.out.Write(entry)
+
+		w.mu.Lock()
+		w.writing = false
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// Dropped returns the number of entries discarded because the buffer was full.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush blocks until every buffered entry has been written or the context is done.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		w.mu.Lock()
+		for w.count > 0 || w.writing {
+			w.cond.Wait()
+		}
+		w.mu.Unlock()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting new entries and waits for the buffer to drain or the context to be done.
+// It does not close the underlying writer, which remains owned by the caller.
+func (w *AsyncWriter) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close is equivalent to Shutdown without a deadline.
+func (w *AsyncWriter) Close() error {
+	return w.Shutdown(context.Background())
+}
@@ -0,0 +1,342 @@
+package logw
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/brokerw"
+)
+
+const (
+	defaultBrokerBatchSize      = 100
+	defaultBrokerFlushInterval  = time.Second
+	defaultBrokerMaxRetries     = 3
+	defaultBrokerRetryBackoff   = 100 * time.Millisecond
+	maxBrokerRetryBackoff       = 5 * time.Second
+	defaultBrokerSendTimeout    = 10 * time.Second
+	defaultBrokerReplayInterval = 30 * time.Second
+	defaultBrokerMaxBuffered    = 10000
+)
+
+// BrokerWriterConfig configures a BrokerWriter.
+type BrokerWriterConfig struct {
+	Topic string // Topic receives one message per log line.
+
+	BatchSize     int           // BatchSize is the number of lines sent per BulkSend (default 100).
+	FlushInterval time.Duration // FlushInterval sends a partial batch after this delay (default 1s).
+	MaxBuffered   int           // MaxBuffered caps in-memory lines; beyond it lines go straight to the spill file (default 10000).
+
+	MaxRetries   int           // MaxRetries is the number of retries after a failed BulkSend (default 3, negative disables retries).
+	RetryBackoff time.Duration // RetryBackoff is the initial backoff, doubled after each retry (default 100ms).
+	SendTimeout  time.Duration // SendTimeout bounds a single BulkSend call (default 10s).
+
+	// SpillPath stores lines that could not be delivered. They are replayed every ReplayInterval.
+	// When empty, undeliverable lines are dropped and reported on stderr.
+	SpillPath      string
+	ReplayInterval time.Duration // ReplayInterval is how often the spill file is replayed (default 30s).
+}
+
+// BrokerWriter turns a brokerw.Producer into a batching io.Writer suitable for LogConfig.BrokerWriter.
+// Each newline-terminated log entry becomes one message on the configured topic.
+type BrokerWriter struct {
+	producer brokerw.Producer
+	cfg      BrokerWriterConfig
+
+	mu      sync.Mutex
+	partial []byte
+	pending [][]byte
+	closed  bool
+
+	spillMu sync.Mutex
+
+	// ctx bounds every BulkSend; it is canceled when the deadline given to Close expires.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	flushCh   chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBrokerWriter starts a BrokerWriter publishing to producer.
+// When it is set as LogConfig.BrokerWriter, Logger.Close closes it once the async writers have drained,
+// so register only logw.Close with gracefulw (gracefulw runs cleanups concurrently):
+//
+//	bw := logw.NewBrokerWriter(producer, logw.BrokerWriterConfig{Topic: "app-logs", SpillPath: "/var/log/app/spill.log"})
+//	_ = logw.Init(&logw.LogConfig{SendToBroker: true, BrokerWriter: bw, Async: true})
+//	gracefulw.Register("Logger", logw.Close)
+//
+// Standalone writers must be closed with Close.
+func NewBrokerWriter(producer brokerw.Producer, cfg BrokerWriterConfig) *BrokerWriter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBrokerBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultBrokerFlushInterval
+	}
+	if cfg.MaxBuffered <= 0 {
+		cfg.MaxBuffered = defaultBrokerMaxBuffered
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultBrokerMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultBrokerRetryBackoff
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = defaultBrokerSendTimeout
+	}
+	if cfg.ReplayInterval <= 0 {
+		cfg.ReplayInterval = defaultBrokerReplayInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &BrokerWriter{
+		producer: producer,
+		cfg:      cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		flushCh:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+// Write splits p on newlines and queues every complete line. Incomplete trailing data is kept
+// until the rest of the line arrives. Write never performs network I/O.
+// It returns ErrWriterClosed once Close has been called.
+func (w *BrokerWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrWriterClosed
+	}
+
+	data := append(w.partial, p...)
+	var overflow [][]byte
+	for {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			break
+		}
+		if idx > 0 {
+			line := make([]byte, idx)
+			copy(line, data[:idx])
+			if len(w.pending) >= w.cfg.MaxBuffered {
+				overflow = append(overflow, line)
+			} else {
+				w.pending = append(w.pending, line)
+			}
+		}
+		data = data[idx+1:]
+	}
+	w.partial = append([]byte(nil), data...)
+	full := len(w.pending) >= w.cfg.BatchSize
+	w.mu.Unlock()
+
+	if len(overflow) > 0 {
+		w.spill(overflow)
+	}
+	if full {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+func (w *BrokerWriter) loop() {
+	defer close(w.done)
+
+	flushTicker := time.NewTicker(w.cfg.FlushInterval)
+	defer flushTicker.Stop()
+	replayTicker := time.NewTicker(w.cfg.ReplayInterval)
+	defer replayTicker.Stop()
+
+	// Lines spilled by a previous process are replayed on the first tick, so a broker outage
+	// at startup does not hold back the loop before it can react to Close.
+	replayed := false
+
+	for {
+		select {
+		case <-flushTicker.C:
+			if !replayed {
+				replayed = true
+				w.replay()
+			}
+			w.flush()
+		case <-w.flushCh:
+			w.flush()
+		case <-replayTicker.C:
+			w.replay()
+		case <-w.stop:
+			w.mu.Lock()
+			if len(w.partial) > 0 {
+				w.pending = append(w.pending, w.partial)
+				w.partial = nil
+			}
+			w.mu.Unlock()
+			w.flush()
+			return
+		}
+	}
+}
+
+// flush sends every pending line in batches, spilling batches that exhaust their retries.
+func (w *BrokerWriter) flush() {
+	w.mu.Lock()
+	lines := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	for len(lines) > 0 {
+		n := min(len(lines), w.cfg.BatchSize)
+		batch := lines[:n]
+		lines = lines[n:]
+
+		if err := w.sendWithRetry(batch); err != nil {
+			w.spill(batch)
+		}
+	}
+}
+
+func (w *BrokerWriter) sendWithRetry(batch [][]byte) error {
+	keys := make([][]byte, len(batch))
+	backoff := w.cfg.RetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			// Retries stop once Close is called; the batch is spilled instead.
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-w.stop:
+				timer.Stop()
+				return err
+			case <-w.ctx.Done():
+				timer.Stop()
+				return err
+			}
+			backoff = min(backoff*2, maxBrokerRetryBackoff)
+		}
+
+		ctx, cancel := context.WithTimeout(w.ctx, w.cfg.SendTimeout)
+		err = w.producer.BulkSend(ctx, w.cfg.Topic, keys, batch)
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// spill appends undeliverable lines to the spill file, or drops them when no spill file is configured.
+// Errors are reported to stderr because the logger cannot log about its own transport.
+func (w *BrokerWriter) spill(lines [][]byte) {
+	if w.cfg.SpillPath == "" {
+		fmt.Fprintf(os.Stderr, "logw: dropped %d log lines for topic %s: broker unavailable\n", len(lines), w.cfg.Topic)
+		return
+	}
+
+	w.spillMu.Lock()
+	defer w.spillMu.Unlock()
+
+	if err := appendLines(w.cfg.SpillPath, lines); err != nil {
+		fmt.Fprintf(os.Stderr, "logw: failed to spill %d log lines to %s: %v\n", len(lines), w.cfg.SpillPath, err)
+	}
+}
+
+func appendLines(path string, lines [][]byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(f)
+	for _, line := range lines {
+		_, _ = bw.Write(line)
+		_ = bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replay re-sends spilled lines. Lines that still cannot be delivered are written back to the spill file.
+func (w *BrokerWriter) replay() {
+	if w.cfg.SpillPath == "" {
+		return
+	}
+
+	// Move the spill file aside so concurrent spills start a fresh file.
+	replayPath := w.cfg.SpillPath + ".replay"
+	w.spillMu.Lock()
+	if _, err := os.Stat(replayPath); errors.Is(err, os.ErrNotExist) {
+		if err := os.Rename(w.cfg.SpillPath, replayPath); err != nil {
+			w.spillMu.Unlock()
+			return
+		}
+	}
+	w.spillMu.Unlock()
+
+	data, err := os.ReadFile(replayPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logw: failed to read spill file %s: %v\n", replayPath, err)
+		return
+	}
+
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	for len(lines) > 0 {
+		n := min(len(lines), w.cfg.BatchSize)
+		if err := w.sendWithRetry(lines[:n]); err != nil {
+			// Broker is still down: keep the remaining lines for the next replay.
+			w.spill(lines)
+			break
+		}
+		lines = lines[n:]
+	}
+
+	if err := os.Remove(replayPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "logw: failed to remove spill file %s: %v\n", replayPath, err)
+	}
+}
+
+// Close flushes pending lines (including an unterminated trailing line) and stops the background worker.
+// Pending lines get a single delivery attempt without retries before they are spilled; when ctx expires,
+// in-flight sends are canceled. It does not close the producer. Its signature matches gracefulw.CleanupFunc.
+func (w *BrokerWriter) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+		close(w.stop)
+	})
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		return ctx.Err()
+	}
+}
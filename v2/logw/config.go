@@ -1,6 +1,9 @@
 package logw
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // LogFormat defines the output format of the log.
 type LogFormat string
@@ -17,11 +20,40 @@ type LogConfig struct {
 	Level  string    // Level specifies the minimum log level (debug, info, warn, error).
 	Format LogFormat // Format specifies the log output format (default is JSON).
 
+	// Redact masks sensitive keys and values (see RedactConfig) on every output destination.
+	// Nil disables redaction; use DefaultRedactKeys and DefaultRedactPatterns for common rules.
+	Redact *RedactConfig
+
+	// OTLP exports every record to an OpenTelemetry collector in addition to the writers above.
+	// Nil disables the export.
+	OTLP *OTLPConfig
+
+	// Sampling drops repetitive records on hot paths (see SamplingConfig). Nil disables sampling.
+	Sampling *SamplingConfig
+
+	// LevelOverrides sets per-logger levels keyed by the name given to Logger.Named (e.g. {"sqlxw": "debug"}).
+	// Both Level and the overrides can be changed at runtime via SetLevel, SetLevelFor or LevelHandler.
+	LevelOverrides map[string]string
+
 	// File Configuration
 	WriteToFile bool
 	FilePath    string // FilePath specifies the location of the log file (e.g., "/var/log/app/service.log").
 
+	// File Rotation (see RotateConfig). Leaving all of them zero keeps appending to a single file.
+	MaxSize        int           // MaxSize is the size in megabytes that triggers a rotation.
+	MaxBackups     int           // MaxBackups is the number of rotated files to retain.
+	MaxAge         int           // MaxAge is the number of days to retain rotated files.
+	Compress       bool          // Compress gzips rotated files.
+	RotateInterval time.Duration // RotateInterval rotates the file on every interval boundary (e.g. 24h).
+
 	// Message Broker Configuration
 	SendToBroker bool
-	BrokerWriter io.Writer // BrokerWriter is an agnostic adapter (e.g., Kafka/NSQ) that implements io.Writer.
+	BrokerWriter io.Writer // BrokerWriter is an agnostic adapter (e.g., Kafka/NSQ) that implements io.Writer. See NewBrokerWriter.
+
+	// Async Configuration
+	// When Async is true, the file and broker destinations are each fronted by an AsyncWriter,
+	// so request goroutines never wait on slow I/O. Register logw.Close with gracefulw to flush on shutdown.
+	Async           bool
+	AsyncBufferSize int            // AsyncBufferSize is the number of buffered entries per destination (default 1024).
+	AsyncOverflow   OverflowPolicy // AsyncOverflow decides between dropping and blocking when full (default OverflowDrop; OverflowBlock can stall callers).
 }
@@ -2,13 +2,18 @@ package logw
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/google/uuid"
 )
 
 type contextKey string
 
-const logIDKey contextKey = "x-log-id"
+const (
+	logIDKey  contextKey = "x-log-id"
+	loggerKey contextKey = "logw-logger"
+	attrsKey  contextKey = "logw-attrs"
+)
 
 // InjectLogID generates a new UUID and injects it into the context as x-log-id.
 // It is highly recommended to call this in the outermost middleware (inbound layer).
@@ -34,3 +39,76 @@ func GetLogID(ctx context.Context) string {
 	}
 	return ""
 }
+
+// NewContext returns a copy of ctx carrying the given Logger.
+// The package-level Ctx* functions will use this Logger instead of the global default.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext retrieves the Logger stored in the context by NewContext.
+// It returns the global default Logger if the context is nil or carries no Logger.
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return Default()
+	}
+	if l, ok := ctx.Value(loggerKey).(*Logger); ok && l != nil {
+		return l
+	}
+	return Default()
+}
+
+// WithAttrs returns a copy of ctx carrying additional attributes that are appended to every
+// subsequent Ctx* log entry. Arguments follow the slog convention: key-value pairs or slog.Attr values.
+// Attributes accumulate across calls, so middleware can attach user ID, tenant, route, etc. once.
+//
+// Example:
+//
+//	ctx = logw.WithAttrs(ctx, "user_id", claims.UserID, slog.String("tenant", claims.Tenant))
+func WithAttrs(ctx context.Context, attrs ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing := GetAttrs(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, argsToAttrs(attrs)...)
+	return context.WithValue(ctx, attrsKey, merged)
+}
+
+// GetAttrs returns the attributes attached to the context by WithAttrs.
+func GetAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey).([]slog.Attr)
+	return attrs
+}
+
+// argsToAttrs converts slog-style variadic arguments into attributes.
+// A dangling value without a key is stored under "!BADKEY", as slog does.
+func argsToAttrs(args []any) []slog.Attr {
+	var attrs []slog.Attr
+	for len(args) > 0 {
+		switch x := args[0].(type) {
+		case slog.Attr:
+			attrs = append(attrs, x)
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				attrs = append(attrs, slog.String("!BADKEY", x))
+				args = nil
+				continue
+			}
+			attrs = append(attrs, slog.Any(x, args[1]))
+			args = args[2:]
+		default:
+			attrs = append(attrs, slog.Any("!BADKEY", x))
+			args = args[1:]
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,242 @@
+package logw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelController holds the minimum log level of a Logger and its per-name overrides.
+// Both can be changed at runtime, e.g. bumping a single subsystem to debug during an incident.
+type LevelController struct {
+	level     slog.LevelVar
+	mu        sync.Mutex // serializes writers of overrides
+	overrides atomic.Pointer[map[string]slog.Level]
+}
+
+// NewLevelController creates a LevelController with the given base level and no overrides.
+func NewLevelController(level slog.Level) *LevelController {
+	c := &LevelController{}
+	c.level.Set(level)
+	c.overrides.Store(&map[string]slog.Level{})
+	return c
+}
+
+// ParseLevel converts a level name (debug, info, warn/warning, error) into a slog.Level.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("logw: unknown log level %q", s)
+	}
+}
+
+// formatLevel renders a slog.Level using the same lowercase names accepted by ParseLevel.
+func formatLevel(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// Level returns the current base level.
+func (c *LevelController) Level() slog.Level {
+	return c.level.Level()
+}
+
+// SetLevel changes the base level applied to every logger without an override.
+func (c *LevelController) SetLevel(level slog.Level) {
+	c.level.Set(level)
+}
+
+// SetOverride sets the minimum level for loggers created with Named(name).
+func (c *LevelController) SetOverride(name string, level slog.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := c.Overrides()
+	next[name] = level
+	c.overrides.Store(&next)
+}
+
+// RemoveOverride drops the override for the given name so it falls back to the base level.
+func (c *LevelController) RemoveOverride(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := c.Overrides()
+	delete(next, name)
+	c.overrides.Store(&next)
+}
+
+// Overrides returns a copy of the current per-name level overrides.
+func (c *LevelController) Overrides() map[string]slog.Level {
+	current := *c.overrides.Load()
+	out := make(map[string]slog.Level, len(current))
+	for k, v := range current {
+		out[k] = v
+	}
+	return out
+}
+
+// Enabled reports whether a record at the given level should be emitted for the named logger.
+func (c *LevelController) Enabled(name string, level slog.Level) bool {
+	if name != "" {
+		if min, ok := (*c.overrides.Load())[name]; ok {
+			return level >= min
+		}
+	}
+	return level >= c.level.Level()
+}
+
+// levelState is the JSON representation exchanged by the level HTTP endpoint.
+type levelState struct {
+	Level     string            `json:"level,omitempty"`
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+func (c *LevelController) state() levelState {
+	st := levelState{Level: formatLevel(c.Level()), Overrides: map[string]string{}}
+	for name, level := range c.Overrides() {
+		st.Overrides[name] = formatLevel(level)
+	}
+	return st
+}
+
+// ServeHTTP exposes the controller over HTTP.
+// GET returns the current levels, PUT updates them using the same JSON shape:
+//
+//	{"level":"info","overrides":{"sqlxw":"debug"}}
+//
+// An override with an empty value is removed.
+func (c *LevelController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req levelState
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("logw: invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := c.apply(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(c.state())
+}
+
+// apply validates the whole request first so a bad entry leaves the levels untouched.
+func (c *LevelController) apply(req levelState) error {
+	var base *slog.Level
+	if req.Level != "" {
+		level, err := ParseLevel(req.Level)
+		if err != nil {
+			return err
+		}
+		base = &level
+	}
+
+	parsed := make(map[string]*slog.Level, len(req.Overrides))
+	for name, value := range req.Overrides {
+		if value == "" {
+			parsed[name] = nil
+			continue
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		parsed[name] = &level
+	}
+
+	if base != nil {
+		c.SetLevel(*base)
+	}
+	for name, level := range parsed {
+		if level == nil {
+			c.RemoveOverride(name)
+		} else {
+			c.SetOverride(name, *level)
+		}
+	}
+	return nil
+}
+
+// levelHandler is the outermost slog.Handler of every Logger.
+// It gates records through the LevelController using the logger name set by Named.
+type levelHandler struct {
+	next   slog.Handler
+	name   string
+	levels *LevelController
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.levels.Enabled(h.name, level) && h.next.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{next: h.next.WithAttrs(attrs), name: h.name, levels: h.levels}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), name: h.name, levels: h.levels}
+}
+
+// Named returns the global default Logger identified by name, so per-name overrides apply to it.
+//
+// Example:
+//
+//	logw.Named("sqlxw").CtxInfof(ctx, "query took %v", d)
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
+
+// SetLevel changes the base level of the global default Logger at runtime.
+func SetLevel(level slog.Level) {
+	Default().Levels().SetLevel(level)
+}
+
+// SetLevelFor overrides the level of loggers created with Named(name) on the global default Logger.
+func SetLevelFor(name string, level slog.Level) {
+	Default().Levels().SetOverride(name, level)
+}
+
+// ResetLevelFor removes a per-name override on the global default Logger.
+func ResetLevelFor(name string) {
+	Default().Levels().RemoveOverride(name)
+}
+
+// LevelHandler returns an http.Handler exposing GET/PUT of the global default Logger's levels.
+// The default Logger is resolved per request, so the handler stays valid across Init calls.
+// It has no authentication: mount it on an internal admin server, never on the public router.
+//
+// Example:
+//
+//	adminMux := http.NewServeMux()
+//	adminMux.Handle("/log-level", logw.LevelHandler())
+//	go http.ListenAndServe("127.0.0.1:9090", adminMux)
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Default().Levels().ServeHTTP(w, r)
+	})
+}
@@ -2,54 +2,127 @@ package logw
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"sync/atomic"
+
+	"github.com/AndreeJait/go-utility/v2/spanw"
 )
 
-var globalLogger *slog.Logger
+// Logger is an instance-based logger wrapping *slog.Logger.
+// Each instance carries its own handler and permanent attributes, so subsystems
+// can hold their own logger (e.g. component=billing) independently of the global default.
+type Logger struct {
+	sl      *slog.Logger
+	levels  *LevelController
+	closers []func(ctx context.Context) error
+}
+
+var defaultLogger atomic.Pointer[Logger]
 
 func init() {
-	globalLogger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	defaultLogger.Store(New(slog.NewJSONHandler(os.Stdout, nil)))
 }
 
-// Init configures and initializes the global logger based on the provided LogConfig.
-// It sets up the output destinations (console, file, and broker), log level, and log format.
-// This function should be called once at the start of the application.
-func Init(cfg *LogConfig) error {
-	var writers []io.Writer
+// New wraps the given slog.Handler into a Logger.
+// A nil handler falls back to a JSON handler writing to os.Stdout.
+// The handler's own level still applies; the Logger's LevelController starts at debug
+// so it only filters further once SetLevel or an override is applied.
+func New(handler slog.Handler) *Logger {
+	return newWithLevels(handler, NewLevelController(slog.LevelDebug))
+}
+
+func newWithLevels(handler slog.Handler, levels *LevelController) *Logger {
+	if handler == nil {
+		handler = slog.NewJSONHandler(os.Stdout, nil)
+	}
+	return &Logger{
+		sl:     slog.New(&levelHandler{next: handler, levels: levels}),
+		levels: levels,
+	}
+}
+
+// NewLogger builds an independent Logger based on the provided LogConfig.
+// Unlike Init, it does not replace the global default logger.
+func NewLogger(cfg *LogConfig) (*Logger, error) {
+	// Validate redaction rules before any file is opened.
+	var redact *redactor
+	if cfg.Redact != nil {
+		r, err := newRedactor(cfg.Redact)
+		if err != nil {
+			return nil, err
+		}
+		redact = r
+	}
+
+	var (
+		writers []io.Writer
+		// Async writers are drained before the files they feed are closed.
+		asyncClosers []func(ctx context.Context) error
+		fileClosers  []func(ctx context.Context) error
+	)
+
+	// wrap fronts a slow destination with an AsyncWriter when enabled.
+	wrap := func(w io.Writer) io.Writer {
+		if !cfg.Async {
+			return w
+		}
+		// Unlike NewAsyncWriter, the config defaults to dropping so requests never wait on a slow destination.
+		policy := cfg.AsyncOverflow
+		if policy == "" {
+			policy = OverflowDrop
+		}
+		aw := NewAsyncWriter(w, cfg.AsyncBufferSize, policy)
+		asyncClosers = append(asyncClosers, aw.Shutdown)
+		return aw
+	}
 
 	writers = append(writers, os.Stdout)
 
 	if cfg.WriteToFile && cfg.FilePath != "" {
-		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		file, err := NewRotatingFile(RotateConfig{
+			Filename:   cfg.FilePath,
+			MaxSize:    cfg.MaxSize,
+			Interval:   cfg.RotateInterval,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to open log file %s: %w", cfg.FilePath, err)
+			return nil, err
 		}
-		writers = append(writers, file)
+		writers = append(writers, wrap(file))
+		fileClosers = append(fileClosers, func(context.Context) error { return file.Close() })
 	}
 
+	// A BrokerWriter is owned by the Logger and closed after the async writer feeding it has drained.
+	var brokerClosers []func(ctx context.Context) error
 	if cfg.SendToBroker && cfg.BrokerWriter != nil {
-		writers = append(writers, cfg.BrokerWriter)
+		writers = append(writers, wrap(cfg.BrokerWriter))
+		if bw, ok := cfg.BrokerWriter.(*BrokerWriter); ok {
+			brokerClosers = append(brokerClosers, bw.Close)
+		}
 	}
 
 	multiWriter := io.MultiWriter(writers...)
 
-	var programLevel slog.LevelVar
-	switch cfg.Level {
-	case "debug":
-		programLevel.Set(slog.LevelDebug)
-	case "error":
-		programLevel.Set(slog.LevelError)
-	case "warn":
-		programLevel.Set(slog.LevelWarn)
-	default:
-		programLevel.Set(slog.LevelInfo)
+	// Unknown levels fall back to info, matching the historical behavior of Init.
+	programLevel, _ := ParseLevel(cfg.Level)
+	levels := NewLevelController(programLevel)
+	for name, value := range cfg.LevelOverrides {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level override for %s: %w", name, err)
+		}
+		levels.SetOverride(name, level)
 	}
 
+	// Level filtering is done by the LevelController, so the inner handler accepts everything.
 	opts := &slog.HandlerOptions{
-		Level: &programLevel,
+		Level: slog.LevelDebug,
 	}
 
 	var handler slog.Handler
@@ -59,70 +132,298 @@ func Init(cfg *LogConfig) error {
 		handler = slog.NewJSONHandler(multiWriter, opts)
 	}
 
-	globalLogger = slog.New(handler)
+	// The OTLP exporter is drained after the sampler's final summary and before the files close.
+	var exportClosers []func(ctx context.Context) error
+	if cfg.OTLP != nil {
+		otlp := NewOTLPHandler(*cfg.OTLP)
+		exportClosers = append(exportClosers, otlp.Close)
+		handler = &fanoutHandler{handlers: []slog.Handler{handler, otlp}}
+	}
+
+	if redact != nil {
+		handler = &redactHandler{next: handler, r: redact}
+	}
+
+	// The sampler is stopped first so its final summary still reaches the async writers.
+	var samplerClosers []func(ctx context.Context) error
+	if cfg.Sampling != nil {
+		sampling := NewSamplingHandler(handler, *cfg.Sampling)
+		samplerClosers = append(samplerClosers, sampling.Close)
+		handler = sampling
+	}
+
+	l := newWithLevels(handler, levels)
+	l.closers = append(samplerClosers, exportClosers...)
+	l.closers = append(l.closers, asyncClosers...)
+	l.closers = append(l.closers, brokerClosers...)
+	l.closers = append(l.closers, fileClosers...)
+	return l, nil
+}
+
+// Init configures and initializes the global logger based on the provided LogConfig.
+// It sets up the output destinations (console, file, and broker), log level, and log format.
+// This function should be called once at the start of the application.
+func Init(cfg *LogConfig) error {
+	l, err := NewLogger(cfg)
+	if err != nil {
+		return err
+	}
+	SetDefault(l)
 	return nil
 }
 
+// Close flushes and closes the outputs of the global default Logger.
+// Register it with gracefulw so buffered logs are not lost on shutdown:
+//
+//	gracefulw.Register("Logger", logw.Close)
+func Close(ctx context.Context) error {
+	return Default().Close(ctx)
+}
+
+// Default returns the global default Logger used by the package-level functions.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the global default Logger. A nil logger is ignored.
+func SetDefault(l *Logger) {
+	if l == nil {
+		return
+	}
+	defaultLogger.Store(l)
+}
+
+// Slog exposes the underlying *slog.Logger for integrations that expect the standard library type.
+func (l *Logger) Slog() *slog.Logger {
+	return l.sl
+}
+
+// Handler returns the slog.Handler backing this Logger.
+func (l *Logger) Handler() slog.Handler {
+	return l.sl.Handler()
+}
+
+// Close flushes asynchronous writers, then closes a BrokerWriter given as LogConfig.BrokerWriter
+// and the files opened by this Logger.
+// Its signature matches gracefulw.CleanupFunc, e.g. gracefulw.Register("Logger", logger.Close).
+func (l *Logger) Close(ctx context.Context) error {
+	var errs []error
+	for _, closeFn := range l.closers {
+		if err := closeFn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Levels returns the LevelController governing this Logger and every Logger derived from it.
+func (l *Logger) Levels() *LevelController {
+	return l.levels
+}
+
+// Named returns a new Logger identified by name (e.g. "sqlxw").
+// The name is attached as the "logger" attribute and is used to look up per-name level overrides.
+func (l *Logger) Named(name string) *Logger {
+	h, ok := l.sl.Handler().(*levelHandler)
+	if !ok {
+		return l.With("logger", name)
+	}
+	return &Logger{
+		sl: slog.New(&levelHandler{
+			next:   h.next.WithAttrs([]slog.Attr{slog.String("logger", name)}),
+			name:   name,
+			levels: h.levels,
+		}),
+		levels:  l.levels,
+		closers: l.closers,
+	}
+}
+
+// With returns a new Logger that includes the given attributes on every log entry.
+// Arguments follow the slog convention: alternating key-value pairs or slog.Attr values.
+func (l *Logger) With(attrs ...any) *Logger {
+	return &Logger{sl: l.sl.With(attrs...), levels: l.levels, closers: l.closers}
+}
+
+// WithGroup returns a new Logger that nests all subsequent attributes under the given group name.
+func (l *Logger) WithGroup(name string) *Logger {
+	return &Logger{sl: l.sl.WithGroup(name), levels: l.levels, closers: l.closers}
+}
+
 // BuildLogAttributes extracts base attributes from the context to be appended to log entries.
-// Currently, it extracts x-log-id, and it serves as an integration point for spanw (tracing).
+// It extracts x-log-id, the W3C trace_id/span_id when present, the spanw execution chain ("trace" and "func") when a span is active,
+// and any attributes attached with WithAttrs.
 func BuildLogAttributes(ctx context.Context) []any {
-	var attrs []any
+	base := contextAttrs(ctx)
+	attrs := make([]any, 0, len(base))
+	for _, a := range base {
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// contextAttrs is the slog.Attr form of BuildLogAttributes, used by the LogAttrs based helpers.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	var attrs []slog.Attr
 	if logID := GetLogID(ctx); logID != "" {
 		attrs = append(attrs, slog.String("x-log-id", logID))
 	}
+	if tc, ok := ctx.Value(traceContextKey).(traceContext); ok {
+		attrs = append(attrs, slog.String("trace_id", tc.traceID))
+		if tc.spanID != "" {
+			attrs = append(attrs, slog.String("span_id", tc.spanID))
+		}
+	}
+	if trace := spanw.GetTraceString(ctx); trace != "" {
+		attrs = append(attrs, slog.String("trace", trace), slog.String("func", spanw.GetCurrentFunc(ctx)))
+	}
+	attrs = append(attrs, GetAttrs(ctx)...)
 	return attrs
 }
 
+// logf formats and emits a printf-style record, skipping the formatting cost when the level is disabled.
+func (l *Logger) logf(ctx context.Context, level slog.Level, format string, args ...any) {
+	if !l.sl.Enabled(ctx, level) {
+		return
+	}
+	l.sl.Log(withTemplate(ctx, format), level, fmt.Sprintf(format, args...), BuildLogAttributes(ctx)...)
+}
+
+// CtxDebug logs a message at Debug level, including base attributes extracted from the context.
+func (l *Logger) CtxDebug(ctx context.Context, msg string) {
+	l.sl.DebugContext(ctx, msg, BuildLogAttributes(ctx)...)
+}
+
+// CtxDebugf logs a formatted message at Debug level, including base attributes extracted from the context.
+// It uses fmt.Sprintf to construct the message from the format string and arguments.
+func (l *Logger) CtxDebugf(ctx context.Context, format string, args ...any) {
+	l.logf(ctx, slog.LevelDebug, format, args...)
+}
+
 // CtxInfo logs a message at Info level, including base attributes extracted from the context.
-func CtxInfo(ctx context.Context, msg string) {
-	globalLogger.InfoContext(ctx, msg, BuildLogAttributes(ctx)...)
+func (l *Logger) CtxInfo(ctx context.Context, msg string) {
+	l.sl.InfoContext(ctx, msg, BuildLogAttributes(ctx)...)
 }
 
 // CtxInfof logs a formatted message at Info level, including base attributes extracted from the context.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func CtxInfof(ctx context.Context, format string, args ...any) {
-	globalLogger.InfoContext(ctx, fmt.Sprintf(format, args...), BuildLogAttributes(ctx)...)
+func (l *Logger) CtxInfof(ctx context.Context, format string, args ...any) {
+	l.logf(ctx, slog.LevelInfo, format, args...)
 }
 
 // CtxWarning logs a message at Warning level, including base attributes extracted from the context.
-func CtxWarning(ctx context.Context, msg string) {
-	globalLogger.WarnContext(ctx, msg, BuildLogAttributes(ctx)...)
+func (l *Logger) CtxWarning(ctx context.Context, msg string) {
+	l.sl.WarnContext(ctx, msg, BuildLogAttributes(ctx)...)
 }
 
 // CtxWarningf logs a formatted message at Warning level, including base attributes extracted from the context.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func CtxWarningf(ctx context.Context, format string, args ...any) {
-	globalLogger.WarnContext(ctx, fmt.Sprintf(format, args...), BuildLogAttributes(ctx)...)
+func (l *Logger) CtxWarningf(ctx context.Context, format string, args ...any) {
+	l.logf(ctx, slog.LevelWarn, format, args...)
 }
 
 // CtxError logs a message at Error level, including base attributes extracted from the context.
-func CtxError(ctx context.Context, msg string) {
-	globalLogger.ErrorContext(ctx, msg, BuildLogAttributes(ctx)...)
+func (l *Logger) CtxError(ctx context.Context, msg string) {
+	l.sl.ErrorContext(ctx, msg, BuildLogAttributes(ctx)...)
 }
 
 // CtxErrorf logs a formatted message at Error level, including base attributes extracted from the context.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func CtxErrorf(ctx context.Context, format string, args ...any) {
-	globalLogger.ErrorContext(ctx, fmt.Sprintf(format, args...), BuildLogAttributes(ctx)...)
+func (l *Logger) CtxErrorf(ctx context.Context, format string, args ...any) {
+	l.logf(ctx, slog.LevelError, format, args...)
+}
+
+// Debug logs a message at Debug level without context attributes.
+func (l *Logger) Debug(msg string) { l.sl.Debug(msg) }
+
+// Debugf logs a formatted message at Debug level without context attributes.
+// It uses fmt.Sprintf to construct the message from the format string and arguments.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelDebug, format, args...)
 }
 
 // Info logs a message at Info level without context attributes.
-func Info(msg string) { globalLogger.Info(msg) }
+func (l *Logger) Info(msg string) { l.sl.Info(msg) }
 
 // Infof logs a formatted message at Info level without context attributes.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func Infof(format string, args ...any) { globalLogger.Info(fmt.Sprintf(format, args...)) }
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelInfo, format, args...)
+}
 
 // Warning logs a message at Warning level without context attributes.
-func Warning(msg string) { globalLogger.Warn(msg) }
+func (l *Logger) Warning(msg string) { l.sl.Warn(msg) }
 
 // Warningf logs a formatted message at Warning level without context attributes.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func Warningf(format string, args ...any) { globalLogger.Warn(fmt.Sprintf(format, args...)) }
+func (l *Logger) Warningf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelWarn, format, args...)
+}
 
 // Error logs a message at Error level without context attributes.
-func Error(msg string) { globalLogger.Error(msg) }
+func (l *Logger) Error(msg string) { l.sl.Error(msg) }
 
 // Errorf logs a formatted message at Error level without context attributes.
 // It uses fmt.Sprintf to construct the message from the format string and arguments.
-func Errorf(format string, args ...any) { globalLogger.Error(fmt.Sprintf(format, args...)) }
+func (l *Logger) Errorf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelError, format, args...)
+}
+
+// CtxDebug logs a message at Debug level using the Logger stored in the context (or the default).
+func CtxDebug(ctx context.Context, msg string) { FromContext(ctx).CtxDebug(ctx, msg) }
+
+// CtxDebugf logs a formatted message at Debug level using the Logger stored in the context (or the default).
+func CtxDebugf(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).CtxDebugf(ctx, format, args...)
+}
+
+// CtxInfo logs a message at Info level using the Logger stored in the context (or the default).
+func CtxInfo(ctx context.Context, msg string) { FromContext(ctx).CtxInfo(ctx, msg) }
+
+// CtxInfof logs a formatted message at Info level using the Logger stored in the context (or the default).
+func CtxInfof(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).CtxInfof(ctx, format, args...)
+}
+
+// CtxWarning logs a message at Warning level using the Logger stored in the context (or the default).
+func CtxWarning(ctx context.Context, msg string) { FromContext(ctx).CtxWarning(ctx, msg) }
+
+// CtxWarningf logs a formatted message at Warning level using the Logger stored in the context (or the default).
+func CtxWarningf(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).CtxWarningf(ctx, format, args...)
+}
+
+// CtxError logs a message at Error level using the Logger stored in the context (or the default).
+func CtxError(ctx context.Context, msg string) { FromContext(ctx).CtxError(ctx, msg) }
+
+// CtxErrorf logs a formatted message at Error level using the Logger stored in the context (or the default).
+func CtxErrorf(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).CtxErrorf(ctx, format, args...)
+}
+
+// Debug logs a message at Debug level using the default Logger.
+func Debug(msg string) { Default().Debug(msg) }
+
+// Debugf logs a formatted message at Debug level using the default Logger.
+func Debugf(format string, args ...any) { Default().Debugf(format, args...) }
+
+// Info logs a message at Info level using the default Logger.
+func Info(msg string) { Default().Info(msg) }
+
+// Infof logs a formatted message at Info level using the default Logger.
+func Infof(format string, args ...any) { Default().Infof(format, args...) }
+
+// Warning logs a message at Warning level using the default Logger.
+func Warning(msg string) { Default().Warning(msg) }
+
+// Warningf logs a formatted message at Warning level using the default Logger.
+func Warningf(format string, args ...any) { Default().Warningf(format, args...) }
+
+// Error logs a message at Error level using the default Logger.
+func Error(msg string) { Default().Error(msg) }
+
+// Errorf logs a formatted message at Error level using the default Logger.
+func Errorf(format string, args ...any) { Default().Errorf(format, args...) }
@@ -3,8 +3,19 @@ package logw
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/spanw"
 )
 
 // TestContextLogID verifies the injection and extraction of x-log-id.
@@ -81,3 +92,660 @@ func TestLoggerOutput(t *testing.T) {
 		buf.Reset()
 	})
 }
+
+// TestLoggerInstance verifies that independent loggers keep their own attributes and output.
+func TestLoggerInstance(t *testing.T) {
+	var bufA, bufB bytes.Buffer
+
+	loggerA, err := NewLogger(&LogConfig{Format: FormatJSON, SendToBroker: true, BrokerWriter: &bufA})
+	if err != nil {
+		t.Fatalf("failed to create logger A: %v", err)
+	}
+	loggerB, err := NewLogger(&LogConfig{Format: FormatJSON, SendToBroker: true, BrokerWriter: &bufB})
+	if err != nil {
+		t.Fatalf("failed to create logger B: %v", err)
+	}
+
+	billing := loggerA.With("component", "billing")
+	billing.WithGroup("invoice").With("id", 42).Info("invoice created")
+	loggerB.Info("unrelated")
+
+	output := bufA.String()
+	if !strings.Contains(output, `"component":"billing"`) {
+		t.Errorf("expected log to contain component attribute, got: %s", output)
+	}
+	if !strings.Contains(output, `"invoice":{"id":42}`) {
+		t.Errorf("expected log to contain grouped attribute, got: %s", output)
+	}
+	if strings.Contains(bufB.String(), "billing") {
+		t.Errorf("expected logger B to be unaffected by logger A attributes, got: %s", bufB.String())
+	}
+}
+
+// TestLoggerFromContext verifies that package-level Ctx functions use the logger stored in the context.
+func TestLoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{Format: FormatJSON, SendToBroker: true, BrokerWriter: &buf})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	if FromContext(context.Background()) != Default() {
+		t.Errorf("expected FromContext to fall back to the default logger")
+	}
+
+	ctx := NewContext(context.Background(), l.With("component", "billing"))
+	CtxInfo(ctx, "charged")
+
+	if !strings.Contains(buf.String(), `"component":"billing"`) {
+		t.Errorf("expected context logger to be used, got: %s", buf.String())
+	}
+}
+
+// TestRuntimeLevels verifies runtime base level changes and per-name overrides.
+func TestRuntimeLevels(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{
+		Level:          "info",
+		LevelOverrides: map[string]string{"sqlxw": "error"},
+		SendToBroker:   true,
+		BrokerWriter:   &buf,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	sqlLogger := l.Named("sqlxw")
+
+	sqlLogger.Info("hidden query")
+	if strings.Contains(buf.String(), "hidden query") {
+		t.Errorf("expected override to suppress info logs, got: %s", buf.String())
+	}
+
+	l.Levels().SetLevel(slog.LevelError)
+	l.Info("hidden info")
+	if strings.Contains(buf.String(), "hidden info") {
+		t.Errorf("expected base level change to suppress info logs, got: %s", buf.String())
+	}
+
+	l.Levels().SetOverride("sqlxw", slog.LevelInfo)
+	sqlLogger.Info("visible query")
+	if !strings.Contains(buf.String(), `"logger":"sqlxw"`) {
+		t.Errorf("expected named logger output after override change, got: %s", buf.String())
+	}
+}
+
+// TestLevelHandler verifies GET/PUT of levels over HTTP.
+func TestLevelHandler(t *testing.T) {
+	c := NewLevelController(slog.LevelInfo)
+
+	req := httptest.NewRequest(http.MethodPut, "/log-level", strings.NewReader(`{"level":"warn","overrides":{"sqlxw":"debug"}}`))
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-level", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `"level":"warn"`) || !strings.Contains(body, `"sqlxw":"debug"`) {
+		t.Errorf("unexpected levels response: %s", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/log-level", strings.NewReader(`{"level":"verbose"}`))
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown level, got %d", rec.Code)
+	}
+	if c.Level() != slog.LevelWarn {
+		t.Errorf("expected level to remain warn after rejected update, got %v", c.Level())
+	}
+}
+
+// TestRotatingFile verifies size-based rotation, compression and MaxBackups retention.
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "service.log")
+
+	rf, err := NewRotatingFile(RotateConfig{Filename: path, MaxSize: 1, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatalf("failed to open rotating file: %v", err)
+	}
+
+	chunk := bytes.Repeat([]byte("x"), megabyte/2+1)
+	for i := 0; i < 4; i++ {
+		if _, err := rf.Write(chunk); err != nil {
+			t.Fatalf("write %d failed: %v", i, err)
+		}
+		// Distinct backup timestamps have millisecond precision.
+		time.Sleep(2 * time.Millisecond)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatalf("failed to close rotating file: %v", err)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "service-*.log.gz"))
+	if err != nil {
+		t.Fatalf("glob failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("expected 2 compressed backups to be retained, got %v", backups)
+	}
+	if plain, _ := filepath.Glob(filepath.Join(dir, "service-*.log")); len(plain) != 0 {
+		t.Errorf("expected no uncompressed backups, got %v", plain)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != int64(len(chunk)) {
+		t.Errorf("expected active file to hold only the last chunk, got %v (err: %v)", info, err)
+	}
+}
+
+// TestRotatingFile_RenameFailure verifies that a failed rotation keeps appending to the original file
+// and that backups are named in UTC.
+func TestRotatingFile_RenameFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "service.log")
+
+	rf, err := NewRotatingFile(RotateConfig{Filename: path})
+	if err != nil {
+		t.Fatalf("failed to open rotating file: %v", err)
+	}
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("first\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	osRename = func(string, string) error { return os.ErrPermission }
+	if err := rf.Rotate(); err == nil {
+		t.Errorf("expected the rotation to fail")
+	}
+	osRename = os.Rename
+
+	if _, err := rf.Write([]byte("second\n")); err != nil {
+		t.Fatalf("expected writes to continue after a failed rotation, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first\nsecond\n" {
+		t.Errorf("expected both entries in the original file, got %q", data)
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+	if err := rf.Rotate(); err != nil {
+		t.Fatalf("rotation failed: %v", err)
+	}
+	backups, _ := rf.backups()
+	if len(backups) != 1 || backups[0].timestamp.Before(before) || backups[0].timestamp.After(time.Now().UTC()) {
+		t.Errorf("expected one backup stamped with the current UTC time, got %v", backups)
+	}
+}
+
+// blockingWriter simulates a slow destination that only proceeds once released.
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// TestAsyncWriter verifies that a slow destination never blocks callers under the drop policy
+// and that Shutdown flushes buffered entries.
+func TestAsyncWriter(t *testing.T) {
+	slow := &blockingWriter{release: make(chan struct{})}
+	aw := NewAsyncWriter(slow, 2, OverflowDrop)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			_, _ = aw.Write([]byte("line\n"))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected writes to return while the destination is blocked")
+	}
+	if aw.Dropped() == 0 {
+		t.Errorf("expected some entries to be dropped")
+	}
+
+	close(slow.release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := aw.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	written := strings.Count(slow.buf.String(), "line")
+	if uint64(written)+aw.Dropped() != 10 {
+		t.Errorf("expected written (%d) + dropped (%d) to equal 10", written, aw.Dropped())
+	}
+	if _, err := aw.Write([]byte("late")); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed after shutdown, got %v", err)
+	}
+}
+
+// TestRedaction verifies key, pattern and struct tag based redaction.
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{
+		Redact:       &RedactConfig{Keys: DefaultRedactKeys, Patterns: DefaultRedactPatterns},
+		SendToBroker: true,
+		BrokerWriter: &buf,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	type payload struct {
+		User     string `json:"user"`
+		PIN      string `json:"pin" logw:"redact"`
+		Password string `json:"password"`
+	}
+
+	l.With("access_token", "abc123").Slog().Info("login from john@example.com",
+		"Authorization", "Bearer xyz",
+		"card", "4111 1111 1111 1111",
+		"order_id", "987650000000000002",
+		"payload", payload{User: "john", PIN: "1234", Password: "hunter2"},
+	)
+
+	output := buf.String()
+	for _, secret := range []string{"abc123", "john@example.com", "xyz", "4111", "1234", "hunter2"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("expected %q to be redacted, got: %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, `"user":"john"`) {
+		t.Errorf("expected non-sensitive struct fields to be kept, got: %s", output)
+	}
+	if !strings.Contains(output, `"order_id":"987650000000000002"`) {
+		t.Errorf("expected a digit run failing the Luhn check to be kept, got: %s", output)
+	}
+
+	if _, err := NewLogger(&LogConfig{Redact: &RedactConfig{Patterns: []string{"("}}}); err == nil {
+		t.Errorf("expected an invalid pattern to be rejected")
+	}
+}
+
+// TestSampling verifies first-N-then-1-in-M sampling keyed by message template and the dropped summary.
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{
+		Sampling:     &SamplingConfig{Interval: time.Minute, First: 2, Thereafter: 5, SummaryInterval: time.Hour},
+		SendToBroker: true,
+		BrokerWriter: &buf,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 12; i++ {
+		l.CtxErrorf(ctx, "dependency %d is flapping", i)
+	}
+	l.CtxError(ctx, "unrelated")
+
+	// First 2 records, then the 7th and 12th (every 5th after First).
+	if got := strings.Count(buf.String(), "is flapping"); got != 4 {
+		t.Errorf("expected 4 sampled records, got %d: %s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "unrelated") {
+		t.Errorf("expected a different template to be sampled independently")
+	}
+
+	if err := l.Close(ctx); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"dropped_total":8`) {
+		t.Errorf("expected a summary of 8 dropped records, got: %s", buf.String())
+	}
+}
+
+// TestSampling_Redact verifies that the dropped summary does not leak messages past the redact handler.
+func TestSampling_Redact(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{
+		Redact:       &RedactConfig{Keys: DefaultRedactKeys, Patterns: DefaultRedactPatterns},
+		Sampling:     &SamplingConfig{Interval: time.Minute, First: 1, SummaryInterval: time.Hour},
+		SendToBroker: true,
+		BrokerWriter: &buf,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		l.CtxError(ctx, "login failed for alice@example.com")
+	}
+	if err := l.Close(ctx); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "alice@example.com") {
+		t.Errorf("expected the email to be redacted everywhere, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"dropped":[{"count":2,"level":"ERROR","message":"login failed for [REDACTED]"}]`) {
+		t.Errorf("expected a redacted summary of 2 dropped records, got: %s", buf.String())
+	}
+}
+
+// TestRateLimit verifies the per-key token bucket.
+func TestRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingConfig{RateLimit: 0.001, Burst: 3, SummaryInterval: time.Hour})
+	defer h.Close(context.Background())
+
+	logger := slog.New(h)
+	for i := 0; i < 10; i++ {
+		logger.Info("hot path")
+	}
+
+	if got := strings.Count(buf.String(), "hot path"); got != 3 {
+		t.Errorf("expected burst of 3 records, got %d", got)
+	}
+	if h.Dropped() != 7 {
+		t.Errorf("expected 7 dropped records, got %d", h.Dropped())
+	}
+}
+
+// TestStructuredLogging verifies the KV and Attr helpers, including the Debug level.
+func TestStructuredLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{Level: "debug", SendToBroker: true, BrokerWriter: &buf})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	ctx := NewContext(InjectLogID(context.Background()), l)
+	CtxInfoKV(ctx, "order created", "order_id", 42, "amount", 9.5)
+	CtxDebugAttrs(ctx, "cache miss", slog.String("key", "user:1"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("failed to decode log line: %v", err)
+	}
+	if entry["order_id"] != float64(42) || entry["amount"] != 9.5 || entry["x-log-id"] != GetLogID(ctx) {
+		t.Errorf("unexpected structured entry: %v", entry)
+	}
+	if !strings.Contains(lines[1], `"level":"DEBUG"`) || !strings.Contains(lines[1], `"key":"user:1"`) {
+		t.Errorf("unexpected debug entry: %s", lines[1])
+	}
+}
+
+// TestContextAttrs verifies WithAttrs propagation and the spanw integration.
+func TestContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := NewLogger(&LogConfig{SendToBroker: true, BrokerWriter: &buf})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	ctx := WithAttrs(context.Background(), "user_id", 7)
+	ctx = WithAttrs(ctx, slog.String("tenant", "acme"))
+	ctx, finish := spanw.Start(ctx, "Handler.Register")
+	defer finish()
+	ctx, finishInner := spanw.Start(ctx, "Usecase.Register")
+	defer finishInner()
+
+	l.CtxInfof(ctx, "registered")
+
+	output := buf.String()
+	for _, want := range []string{
+		`"user_id":7`,
+		`"tenant":"acme"`,
+		`"trace":"Handler.Register -> Usecase.Register"`,
+		`"func":"Usecase.Register"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected log to contain %s, got: %s", want, output)
+		}
+	}
+
+	if attrs := GetAttrs(context.Background()); len(attrs) != 0 {
+		t.Errorf("expected no attributes on a bare context, got %v", attrs)
+	}
+}
+
+// fakeProducer is an in-memory brokerw.Producer that can simulate an outage.
+type fakeProducer struct {
+	mu       sync.Mutex
+	down     bool
+	messages []string
+}
+
+func (p *fakeProducer) Send(ctx context.Context, topic string, key, payload []byte) error {
+	return p.BulkSend(ctx, topic, [][]byte{key}, [][]byte{payload})
+}
+
+func (p *fakeProducer) BulkSend(ctx context.Context, topic string, keys, payloads [][]byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down {
+		return errors.New("broker unavailable")
+	}
+	for _, payload := range payloads {
+		p.messages = append(p.messages, string(payload))
+	}
+	return nil
+}
+
+func (p *fakeProducer) Close() error { return nil }
+
+func (p *fakeProducer) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func (p *fakeProducer) received() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.messages...)
+}
+
+// TestBrokerWriter verifies line splitting, spilling during an outage and replay after recovery.
+func TestBrokerWriter(t *testing.T) {
+	producer := &fakeProducer{down: true}
+	spillPath := filepath.Join(t.TempDir(), "spill.log")
+
+	bw := NewBrokerWriter(producer, BrokerWriterConfig{
+		Topic:          "logs",
+		BatchSize:      2,
+		FlushInterval:  10 * time.Millisecond,
+		MaxRetries:     -1,
+		SpillPath:      spillPath,
+		ReplayInterval: 20 * time.Millisecond,
+	})
+
+	_, _ = bw.Write([]byte("first\nsec"))
+	_, _ = bw.Write([]byte("ond\nthird\n"))
+
+	waitFor(t, func() bool {
+		data, _ := os.ReadFile(spillPath)
+		return strings.Count(string(data), "\n") == 3
+	})
+
+	producer.setDown(false)
+	waitFor(t, func() bool { return len(producer.received()) == 3 })
+
+	_, _ = bw.Write([]byte("unterminated"))
+	if err := bw.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close broker writer: %v", err)
+	}
+
+	want := []string{"first", "second", "third", "unterminated"}
+	if got := producer.received(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected messages %v, got %v", want, got)
+	}
+}
+
+// TestBrokerWriter_Close verifies that Close cuts retries short, that Logger.Close owns a
+// configured BrokerWriter and that writes after Close are rejected.
+func TestBrokerWriter_Close(t *testing.T) {
+	producer := &fakeProducer{down: true}
+	spillPath := filepath.Join(t.TempDir(), "spill.log")
+
+	bw := NewBrokerWriter(producer, BrokerWriterConfig{
+		Topic:         "logs",
+		FlushInterval: 10 * time.Millisecond,
+		MaxRetries:    10,
+		RetryBackoff:  time.Hour,
+		SpillPath:     spillPath,
+	})
+
+	l, err := NewLogger(&LogConfig{SendToBroker: true, BrokerWriter: bw, Async: true})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	l.Info("during outage")
+	// Let the flush start its hour-long backoff.
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.Close(ctx); err != nil {
+		t.Fatalf("expected Close to cut the retries short, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(spillPath); !strings.Contains(string(data), "during outage") {
+		t.Errorf("expected the undelivered line to be spilled, got %q", data)
+	}
+	if _, err := bw.Write([]byte("late\n")); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed after Logger.Close, got %v", err)
+	}
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestOTLPExport verifies the OTLP/HTTP JSON export and trace correlation against a stand-in collector.
+func TestOTLPExport(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []map[string]any
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected export request: %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var req struct {
+			ResourceLogs []struct {
+				ScopeLogs []struct {
+					LogRecords []map[string]any `json:"logRecords"`
+				} `json:"scopeLogs"`
+			} `json:"resourceLogs"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode export request: %v", err)
+		}
+		mu.Lock()
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				received = append(received, sl.LogRecords...)
+			}
+		}
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	l, err := NewLogger(&LogConfig{
+		OTLP:         &OTLPConfig{Endpoint: collector.URL, ServiceName: "billing", FlushInterval: time.Hour},
+		SendToBroker: true,
+		BrokerWriter: &bytes.Buffer{},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	traced := ContextFromTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	l.CtxWarningKV(traced, "slow upstream", "latency_ms", 1200)
+
+	legacy := InjectLogID(context.Background())
+	l.CtxInfo(legacy, "legacy request")
+
+	l.WithGroup("http").CtxInfo(traced, "grouped request")
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 3 {
+		t.Fatalf("expected 3 exported records, got %d", len(received))
+	}
+
+	first := received[0]
+	if first["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || first["spanId"] != "00f067aa0ba902b7" {
+		t.Errorf("expected W3C trace context on the record, got %v", first)
+	}
+	if first["severityNumber"] != float64(13) {
+		t.Errorf("expected WARN severity number 13, got %v", first["severityNumber"])
+	}
+
+	wantTrace := strings.ReplaceAll(GetLogID(legacy), "-", "")
+	if received[1]["traceId"] != wantTrace {
+		t.Errorf("expected trace ID derived from x-log-id %s, got %v", wantTrace, received[1]["traceId"])
+	}
+
+	grouped, _ := json.Marshal(received[2]["attributes"])
+	if strings.Contains(string(grouped), "trace_id") || strings.Contains(string(grouped), "span_id") {
+		t.Errorf("expected trace_id/span_id to be stripped from grouped attributes, got %s", grouped)
+	}
+}
+
+// TestContextFromTraceparent verifies that only well-formed W3C traceparent headers are accepted.
+func TestContextFromTraceparent(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceID},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", traceID},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", ""},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+	}
+
+	for _, tt := range tests {
+		got, _ := GetTraceContext(ContextFromTraceparent(context.Background(), tt.header))
+		if got != tt.want {
+			t.Errorf("ContextFromTraceparent(%q): expected trace ID %q, got %q", tt.header, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package logwtest provides an in-memory recorder for asserting on logw output in tests.
+// It replaces capturing a bytes.Buffer through LogConfig.BrokerWriter and matching with strings.Contains.
+package logwtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+// Record is a parsed log entry captured by a Recorder.
+// Attributes of groups are flattened using dotted keys (e.g. "invoice.id").
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// Recorder stores every record logged through its handler. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Handler returns a slog.Handler that records into r. It accepts every level.
+func (r *Recorder) Handler() slog.Handler {
+	return &recordingHandler{rec: r}
+}
+
+// Logger returns a logw.Logger that records into r.
+func (r *Recorder) Logger() *logw.Logger {
+	return logw.New(r.Handler())
+}
+
+// Context returns a copy of ctx carrying a Logger that records into r.
+// Package-level logw.Ctx* calls made with the returned context are captured without touching
+// the global default, which keeps parallel tests isolated.
+func (r *Recorder) Context(ctx context.Context) context.Context {
+	return logw.NewContext(ctx, r.Logger())
+}
+
+// Records returns a copy of all captured records in logging order.
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// Reset discards all captured records.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+// Find returns the records at the given level whose message contains msgSubstring
+// and whose attributes include every key-value pair in attrs.
+func (r *Recorder) Find(level slog.Level, msgSubstring string, attrs ...any) []Record {
+	want := toPairs(attrs)
+
+	var found []Record
+	for _, rec := range r.Records() {
+		if rec.Level == level && strings.Contains(rec.Message, msgSubstring) && rec.hasAttrs(want) {
+			found = append(found, rec)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless a matching record was captured. See Find for matching rules.
+func (r *Recorder) AssertLogged(t testing.TB, level slog.Level, msgSubstring string, attrs ...any) {
+	t.Helper()
+	if len(r.Find(level, msgSubstring, attrs...)) == 0 {
+		t.Errorf("logwtest: expected a %s record containing %q with attrs %v, got:\n%s", level, msgSubstring, attrs, r.dump())
+	}
+}
+
+// AssertNotLogged fails the test if a matching record was captured. See Find for matching rules.
+func (r *Recorder) AssertNotLogged(t testing.TB, level slog.Level, msgSubstring string, attrs ...any) {
+	t.Helper()
+	if found := r.Find(level, msgSubstring, attrs...); len(found) > 0 {
+		t.Errorf("logwtest: expected no %s record containing %q with attrs %v, got %d", level, msgSubstring, attrs, len(found))
+	}
+}
+
+func (r *Recorder) dump() string {
+	var sb strings.Builder
+	for _, rec := range r.Records() {
+		fmt.Fprintf(&sb, "  %s %q %v\n", rec.Level, rec.Message, rec.Attrs)
+	}
+	if sb.Len() == 0 {
+		return "  (no records)"
+	}
+	return sb.String()
+}
+
+func (r *Recorder) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+}
+
+// override is the Recorder installed by Override together with the Logger it made the default.
+type override struct {
+	rec    *Recorder
+	logger *logw.Logger
+}
+
+var (
+	overrideMu sync.Mutex
+	active     *override
+)
+
+// Override replaces the global logw default Logger with a fresh Recorder for the duration of the test.
+// The previous default is restored on t.Cleanup. Because the default is global, tests using Override
+// must not run in parallel with each other; prefer Recorder.Context for parallel tests.
+func Override(t testing.TB) *Recorder {
+	t.Helper()
+
+	rec := NewRecorder()
+	current := &override{rec: rec, logger: rec.Logger()}
+
+	overrideMu.Lock()
+	previous, previousDefault := active, logw.Default()
+	active = current
+	logw.SetDefault(current.logger)
+	overrideMu.Unlock()
+
+	t.Cleanup(func() {
+		overrideMu.Lock()
+		defer overrideMu.Unlock()
+		logw.SetDefault(previousDefault)
+		active = previous
+	})
+	return rec
+}
+
+// AssertLogged asserts against the Recorder currently installed as the global default by Override,
+// so it also works inside subtests of the test that called Override.
+//
+// Example:
+//
+//	logwtest.Override(t)
+//	svc.Charge(ctx, order)
+//	logwtest.AssertLogged(t, slog.LevelInfo, "charged", "order_id", 42)
+func AssertLogged(t testing.TB, level slog.Level, msgSubstring string, attrs ...any) {
+	t.Helper()
+
+	overrideMu.Lock()
+	current := active
+	overrideMu.Unlock()
+
+	if current == nil || logw.Default() != current.logger {
+		t.Fatalf("logwtest: AssertLogged called without Override(t)")
+		return
+	}
+	current.rec.AssertLogged(t, level, msgSubstring, attrs...)
+}
+
+type pair struct {
+	key   string
+	value any
+}
+
+// toPairs converts alternating key-value arguments (or slog.Attr values) into pairs.
+func toPairs(args []any) []pair {
+	var pairs []pair
+	for len(args) > 0 {
+		switch x := args[0].(type) {
+		case slog.Attr:
+			pairs = append(pairs, pair{key: x.Key, value: x.Value.Resolve().Any()})
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				pairs = append(pairs, pair{key: x})
+				args = nil
+				continue
+			}
+			pairs = append(pairs, pair{key: x, value: args[1]})
+			args = args[2:]
+		default:
+			pairs = append(pairs, pair{key: fmt.Sprint(x)})
+			args = args[1:]
+		}
+	}
+	return pairs
+}
+
+// hasAttrs compares values by their printed form so that e.g. int and int64 match.
+func (rec Record) hasAttrs(want []pair) bool {
+	for _, p := range want {
+		got, ok := rec.Attrs[p.key]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(p.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordingHandler is the slog.Handler behind a Recorder.
+type recordingHandler struct {
+	rec    *Recorder
+	attrs  []slog.Attr // already prefixed with their group path
+	prefix string
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]any, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		flatten(attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(attrs, h.prefix, a)
+		return true
+	})
+
+	h.rec.add(Record{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: attrs})
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &recordingHandler{rec: h.rec, prefix: h.prefix}
+	next.attrs = append(next.attrs, h.attrs...)
+	for _, a := range attrs {
+		next.attrs = append(next.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return next
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &recordingHandler{rec: h.rec, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func flatten(out map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flatten(out, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	out[prefix+a.Key] = v.Any()
+}
@@ -0,0 +1,54 @@
+package logwtest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/AndreeJait/go-utility/v2/logw"
+)
+
+func TestRecorder_Context(t *testing.T) {
+	t.Parallel()
+
+	rec := NewRecorder()
+	ctx := rec.Context(logw.InjectLogID(context.Background()))
+
+	logw.CtxInfoKV(ctx, "order created", "order_id", 42)
+	logw.FromContext(ctx).With("component", "billing").WithGroup("invoice").Slog().Warn("late", "id", 7)
+
+	rec.AssertLogged(t, slog.LevelInfo, "order created", "order_id", 42, "x-log-id", logw.GetLogID(ctx))
+	rec.AssertLogged(t, slog.LevelWarn, "late", "component", "billing", "invoice.id", 7)
+	rec.AssertNotLogged(t, slog.LevelError, "order created")
+
+	if len(rec.Records()) != 2 {
+		t.Errorf("expected 2 records, got %d", len(rec.Records()))
+	}
+}
+
+func TestOverride_RestoresDefault(t *testing.T) {
+	previous := logw.Default()
+
+	t.Run("override", func(t *testing.T) {
+		Override(t)
+		logw.Errorf("payment %d failed", 9)
+		AssertLogged(t, slog.LevelError, "payment 9 failed")
+
+		if logw.Default() == previous {
+			t.Errorf("expected the default logger to be replaced")
+		}
+	})
+
+	if logw.Default() != previous {
+		t.Errorf("expected the default logger to be restored after cleanup")
+	}
+}
+
+func TestAssertLogged_Subtest(t *testing.T) {
+	Override(t)
+
+	t.Run("child", func(t *testing.T) {
+		logw.Infof("refund %d issued", 3)
+		AssertLogged(t, slog.LevelInfo, "refund 3 issued")
+	})
+}
@@ -0,0 +1,401 @@
+package logw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOTLPBatchSize     = 512
+	defaultOTLPFlushInterval = 2 * time.Second
+	defaultOTLPTimeout       = 10 * time.Second
+	defaultOTLPMaxQueue      = 8192
+	otlpLogsPath             = "/v1/logs"
+	otlpScopeName            = "github.com/AndreeJait/go-utility/v2/logw"
+)
+
+// OTLPConfig configures the export of log records to an OpenTelemetry collector over OTLP/HTTP (JSON encoding).
+type OTLPConfig struct {
+	Endpoint    string            // Endpoint is the collector base URL, e.g. "http://localhost:4318" ("/v1/logs" is appended).
+	ServiceName string            // ServiceName is exported as the service.name resource attribute.
+	Resource    map[string]string // Resource holds additional resource attributes (e.g. deployment.environment).
+	Headers     map[string]string // Headers are added to every export request (e.g. authentication).
+
+	BatchSize     int           // BatchSize is the number of records per export request (default 512).
+	FlushInterval time.Duration // FlushInterval exports a partial batch after this delay (default 2s).
+	MaxQueue      int           // MaxQueue bounds buffered records; newer records are dropped when full (default 8192).
+	Timeout       time.Duration // Timeout bounds a single export request (default 10s).
+	Client        *http.Client  // Client overrides the HTTP client used for export.
+}
+
+// OTLP JSON data model (subset of opentelemetry-proto ExportLogsServiceRequest).
+type (
+	otlpAnyValue struct {
+		StringValue *string        `json:"stringValue,omitempty"`
+		BoolValue   *bool          `json:"boolValue,omitempty"`
+		IntValue    *string        `json:"intValue,omitempty"`
+		DoubleValue *float64       `json:"doubleValue,omitempty"`
+		KvlistValue *otlpKeyValues `json:"kvlistValue,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpKeyValues struct {
+		Values []otlpKeyValue `json:"values"`
+	}
+	otlpLogRecord struct {
+		TimeUnixNano         string         `json:"timeUnixNano"`
+		ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+		SeverityNumber       int            `json:"severityNumber"`
+		SeverityText         string         `json:"severityText"`
+		Body                 otlpAnyValue   `json:"body"`
+		Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+		TraceID              string         `json:"traceId,omitempty"`
+		SpanID               string         `json:"spanId,omitempty"`
+	}
+	otlpScopeLogs struct {
+		Scope      map[string]string `json:"scope"`
+		LogRecords []otlpLogRecord   `json:"logRecords"`
+	}
+	otlpResourceLogs struct {
+		Resource  map[string][]otlpKeyValue `json:"resource"`
+		ScopeLogs []otlpScopeLogs           `json:"scopeLogs"`
+	}
+	otlpExportRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+)
+
+// otlpExporter holds the queue shared by an OTLPHandler and every handler derived from it.
+type otlpExporter struct {
+	cfg      OTLPConfig
+	url      string
+	resource []otlpKeyValue
+
+	mu      sync.Mutex
+	queue   []otlpLogRecord
+	dropped uint64
+
+	flushCh  chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// OTLPHandler is a slog.Handler exporting records in the OTel log data model.
+// trace_id/span_id come from the W3C trace context, or from x-log-id when none exists (see GetTraceContext).
+type OTLPHandler struct {
+	exp    *otlpExporter
+	attrs  []otlpKeyValue
+	prefix string
+}
+
+// NewOTLPHandler starts an exporter for the given collector. Call Close to flush on shutdown.
+func NewOTLPHandler(cfg OTLPConfig) *OTLPHandler {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultOTLPBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultOTLPFlushInterval
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = defaultOTLPMaxQueue
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultOTLPTimeout
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	resource := []otlpKeyValue{otlpString("service.name", cfg.ServiceName)}
+	for k, v := range cfg.Resource {
+		resource = append(resource, otlpString(k, v))
+	}
+
+	exp := &otlpExporter{
+		cfg:      cfg,
+		url:      strings.TrimSuffix(cfg.Endpoint, "/") + otlpLogsPath,
+		resource: resource,
+		flushCh:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go exp.loop()
+
+	return &OTLPHandler{exp: exp}
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+// otlpSeverity maps slog levels onto the OTel severity numbers (DEBUG=5, INFO=9, WARN=13, ERROR=17).
+func otlpSeverity(level slog.Level) int {
+	return max(1, min(24, 9+int(level)))
+}
+
+func otlpValue(v slog.Value) otlpAnyValue {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	case slog.KindBool:
+		b := v.Bool()
+		return otlpAnyValue{BoolValue: &b}
+	case slog.KindInt64:
+		s := strconv.FormatInt(v.Int64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindUint64:
+		s := strconv.FormatUint(v.Uint64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindFloat64:
+		f := v.Float64()
+		return otlpAnyValue{DoubleValue: &f}
+	case slog.KindDuration:
+		s := strconv.FormatInt(int64(v.Duration()), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindTime:
+		s := v.Time().Format(time.RFC3339Nano)
+		return otlpAnyValue{StringValue: &s}
+	case slog.KindGroup:
+		kvs := &otlpKeyValues{}
+		for _, a := range v.Group() {
+			kvs.Values = append(kvs.Values, otlpKeyValue{Key: a.Key, Value: otlpValue(a.Value)})
+		}
+		return otlpAnyValue{KvlistValue: kvs}
+	default:
+		var s string
+		if err, ok := v.Any().(error); ok {
+			s = err.Error()
+		} else if b, err := json.Marshal(v.Any()); err == nil {
+			s = string(b)
+		} else {
+			s = fmt.Sprint(v.Any())
+		}
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+// appendAttr flattens groups into dotted keys, following OTel attribute naming conventions.
+// trace_id/span_id attributes are skipped in any group because they map onto dedicated record fields.
+func appendAttr(out []otlpKeyValue, prefix string, a slog.Attr) []otlpKeyValue {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			out = appendAttr(out, groupPrefix, ga)
+		}
+		return out
+	}
+	if a.Key == "" || a.Key == "trace_id" || a.Key == "span_id" {
+		return out
+	}
+	return append(out, otlpKeyValue{Key: prefix + a.Key, Value: otlpValue(v)})
+}
+
+func (h *OTLPHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *OTLPHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := append([]otlpKeyValue(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	msg := r.Message
+	traceID, spanID := GetTraceContext(ctx)
+	rec := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(r.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       otlpSeverity(r.Level),
+		SeverityText:         r.Level.String(),
+		Body:                 otlpAnyValue{StringValue: &msg},
+		Attributes:           attrs,
+		TraceID:              traceID,
+		SpanID:               spanID,
+	}
+
+	h.exp.enqueue(rec)
+	return nil
+}
+
+func (h *OTLPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &OTLPHandler{exp: h.exp, prefix: h.prefix}
+	next.attrs = append(next.attrs, h.attrs...)
+	for _, a := range attrs {
+		next.attrs = appendAttr(next.attrs, h.prefix, a)
+	}
+	return next
+}
+
+func (h *OTLPHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &OTLPHandler{exp: h.exp, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// Close exports the remaining records and stops the exporter. Its signature matches gracefulw.CleanupFunc.
+func (h *OTLPHandler) Close(ctx context.Context) error {
+	h.exp.stopOnce.Do(func() { close(h.exp.stop) })
+	select {
+	case <-h.exp.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *otlpExporter) enqueue(rec otlpLogRecord) {
+	e.mu.Lock()
+	if len(e.queue) >= e.cfg.MaxQueue {
+		e.dropped++
+		e.mu.Unlock()
+		return
+	}
+	e.queue = append(e.queue, rec)
+	full := len(e.queue) >= e.cfg.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *otlpExporter) loop() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.flush()
+		case <-e.flushCh:
+			e.flush()
+		case <-e.stop:
+			e.flush()
+			return
+		}
+	}
+}
+
+func (e *otlpExporter) flush() {
+	e.mu.Lock()
+	records := e.queue
+	e.queue = nil
+	dropped := e.dropped
+	e.dropped = 0
+	e.mu.Unlock()
+
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "logw: dropped %d log records because the OTLP queue was full\n", dropped)
+	}
+
+	for len(records) > 0 {
+		n := min(len(records), e.cfg.BatchSize)
+		if err := e.export(records[:n]); err != nil {
+			// The logger cannot log about its own transport.
+			fmt.Fprintf(os.Stderr, "logw: failed to export %d log records to %s: %v\n", n, e.url, err)
+		}
+		records = records[n:]
+	}
+}
+
+func (e *otlpExporter) export(records []otlpLogRecord) error {
+	body, err := json.Marshal(otlpExportRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: map[string][]otlpKeyValue{"attributes": e.resource},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      map[string]string{"name": otlpScopeName},
+			LogRecords: records,
+		}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// fanoutHandler forwards every record to all of its handlers.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, next := range h.handlers {
+		if next.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, next := range h.handlers {
+		if !next.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := next.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		next[i] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: next}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	next := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		next[i] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: next}
+}
@@ -0,0 +1,278 @@
+package logw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultRedactMask = "[REDACTED]"
+	redactTagName     = "logw"
+	redactTagValue    = "redact"
+	maxRedactDepth    = 8
+)
+
+// DefaultRedactKeys are common sensitive attribute names. Matching is case-insensitive
+// and supports glob patterns (e.g. "*_token").
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "authorization", "cookie",
+	"*_token", "token", "api_key", "apikey", "*_secret",
+}
+
+// PANPattern matches card numbers (13-19 digits, optionally separated by spaces or dashes).
+// Matches are only masked when they pass the Luhn check, so timestamps and numeric IDs survive.
+const PANPattern = `\b(?:\d[ \-]?){12,18}\d\b`
+
+// DefaultRedactPatterns are regular expressions for sensitive values embedded in strings:
+// email addresses, card numbers (PAN) and JWTs.
+var DefaultRedactPatterns = []string{
+	`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	PANPattern,
+	`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`,
+}
+
+// RedactConfig describes which attributes and values are masked before a record is written.
+type RedactConfig struct {
+	Keys     []string // Keys are attribute/field names whose values are fully masked (glob patterns allowed).
+	Patterns []string // Patterns are regular expressions masked inside messages and string values.
+	Mask     string   // Mask replaces redacted content (default "[REDACTED]").
+}
+
+// redactor holds the compiled form of a RedactConfig.
+type redactor struct {
+	keys     []string
+	patterns []redactPattern
+	mask     string
+}
+
+// redactPattern is a compiled value pattern; luhn restricts masking to matches passing the Luhn check.
+type redactPattern struct {
+	re   *regexp.Regexp
+	luhn bool
+}
+
+func newRedactor(cfg *RedactConfig) (*redactor, error) {
+	r := &redactor{mask: cfg.Mask}
+	if r.mask == "" {
+		r.mask = defaultRedactMask
+	}
+	for _, k := range cfg.Keys {
+		k = strings.ToLower(k)
+		if _, err := path.Match(k, ""); err != nil {
+			return nil, fmt.Errorf("logw: invalid redact key pattern %q: %w", k, err)
+		}
+		r.keys = append(r.keys, k)
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("logw: invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, redactPattern{re: re, luhn: p == PANPattern})
+	}
+	return r, nil
+}
+
+func (r *redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if ok, _ := path.Match(k, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, p := range r.patterns {
+		if !p.luhn {
+			s = p.re.ReplaceAllString(s, r.mask)
+			continue
+		}
+		s = p.re.ReplaceAllStringFunc(s, func(m string) string {
+			if !luhnValid(m) {
+				return m
+			}
+			return r.mask
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by card numbers.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func (r *redactor) redactAttr(a slog.Attr) slog.Attr {
+	if r.matchKey(a.Key) {
+		return slog.String(a.Key, r.mask)
+	}
+	return slog.Attr{Key: a.Key, Value: r.redactValue(a.Value.Resolve(), 0)}
+}
+
+func (r *redactor) redactAttrs(attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = r.redactAttr(a)
+	}
+	return out
+}
+
+func (r *redactor) redactValue(v slog.Value, depth int) slog.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(r.redactString(v.String()))
+	case slog.KindGroup:
+		return slog.GroupValue(r.redactAttrs(v.Group())...)
+	case slog.KindAny:
+		if _, ok := v.Any().(error); ok {
+			return slog.StringValue(r.redactString(v.String()))
+		}
+		return slog.AnyValue(r.redactReflect(reflect.ValueOf(v.Any()), depth))
+	default:
+		return v
+	}
+}
+
+// redactReflect walks structs, maps and slices, masking fields tagged `logw:"redact"`,
+// fields whose name matches a key rule, and string values matching a pattern.
+// Structs are rendered as maps keyed by their json name.
+func (r *redactor) redactReflect(rv reflect.Value, depth int) any {
+	if !rv.IsValid() {
+		return nil
+	}
+	if depth > maxRedactDepth {
+		return r.mask
+	}
+	if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface && rv.CanInterface() {
+		if _, ok := rv.Interface().(json.Marshaler); ok {
+			return rv.Interface()
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return r.redactReflect(rv.Elem(), depth+1)
+
+	case reflect.String:
+		return r.redactString(rv.String())
+
+	case reflect.Struct:
+		out := make(map[string]any, rv.NumField())
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				jsonName, _, _ := strings.Cut(tag, ",")
+				if jsonName == "-" {
+					continue
+				}
+				if jsonName != "" {
+					name = jsonName
+				}
+			}
+			if field.Tag.Get(redactTagName) == redactTagValue || r.matchKey(name) {
+				out[name] = r.mask
+				continue
+			}
+			out[name] = r.redactReflect(rv.Field(i), depth+1)
+		}
+		return out
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface()
+		}
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if r.matchKey(key) {
+				out[key] = r.mask
+				continue
+			}
+			out[key] = r.redactReflect(iter.Value(), depth+1)
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface()
+		}
+		out := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out[i] = r.redactReflect(rv.Index(i), depth+1)
+		}
+		return out
+
+	default:
+		return rv.Interface()
+	}
+}
+
+// redactHandler masks sensitive data in the message and attributes before delegating to next.
+// It sits in front of the shared output, so every destination receives the redacted record.
+type redactHandler struct {
+	next slog.Handler
+	r    *redactor
+}
+
+// NewRedactHandler wraps next with a handler that applies the given redaction rules.
+func NewRedactHandler(next slog.Handler, cfg *RedactConfig) (slog.Handler, error) {
+	r, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &redactHandler{next: next, r: r}, nil
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, rec slog.Record) error {
+	out := slog.NewRecord(rec.Time, rec.Level, h.r.redactString(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.r.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &redactHandler{next: h.next.WithAttrs(h.r.redactAttrs(attrs)), r: h.r}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name), r: h.r}
+}
@@ -0,0 +1,284 @@
+package logw
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	megabyte        = 1024 * 1024
+	backupTimeFmt   = "2006-01-02T15-04-05.000"
+	compressSuffix  = ".gz"
+	defaultFileMode = 0666
+)
+
+// osRename is replaced in tests to simulate a failing rotation.
+var osRename = os.Rename
+
+// RotateConfig holds the rotation and retention policy of a RotatingFile.
+type RotateConfig struct {
+	Filename string        // Filename is the active log file; rotated files are written next to it.
+	MaxSize  int           // MaxSize is the size in megabytes that triggers a rotation (0 disables size rotation).
+	Interval time.Duration // Interval rotates the file on every boundary (e.g. 24h rotates daily at 00:00 UTC). 0 disables it.
+
+	MaxBackups int  // MaxBackups is the number of rotated files to retain (0 retains all).
+	MaxAge     int  // MaxAge is the number of days to retain rotated files (0 disables age-based cleanup).
+	Compress   bool // Compress gzips rotated files.
+}
+
+// RotatingFile is an io.WriteCloser that rotates the underlying file by size and/or time,
+// gzips rotated files and prunes them according to the retention policy.
+// Rotated files are named "<name>-<UTC timestamp><ext>", e.g. "service-2024-01-02T15-04-05.000.log.gz".
+type RotatingFile struct {
+	cfg RotateConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	millMu sync.Mutex
+	millWg sync.WaitGroup
+}
+
+// NewRotatingFile opens (or creates) the configured file in append mode.
+func NewRotatingFile(cfg RotateConfig) (*RotatingFile, error) {
+	if cfg.Filename == "" {
+		return nil, fmt.Errorf("logw: rotating file requires a filename")
+	}
+	r := &RotatingFile{cfg: cfg}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.cfg.Filename), 0755); err != nil {
+		return fmt.Errorf("logw: failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(r.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", r.cfg.Filename, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("logw: failed to stat log file %s: %w", r.cfg.Filename, err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = time.Now()
+	if r.size > 0 {
+		// An existing file belongs to the period it was last written in.
+		r.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write appends p to the active file, rotating first if p would exceed MaxSize
+// or if an Interval boundary has been crossed since the file was opened.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			if r.file == nil {
+				return 0, err
+			}
+			// The original file was reopened, so keep appending rather than losing the entry.
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) shouldRotate(incoming int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.cfg.MaxSize > 0 && r.size+incoming > int64(r.cfg.MaxSize)*megabyte {
+		return true
+	}
+	if r.cfg.Interval > 0 && !time.Now().Truncate(r.cfg.Interval).Equal(r.openedAt.Truncate(r.cfg.Interval)) {
+		return true
+	}
+	return false
+}
+
+// Rotate forces a rotation of the active file.
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotate()
+}
+
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return fmt.Errorf("logw: failed to close log file: %w", err)
+		}
+		r.file = nil
+	}
+
+	if err := osRename(r.cfg.Filename, r.backupName(time.Now().UTC())); err != nil && !os.IsNotExist(err) {
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("logw: failed to rotate log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.millWg.Add(1)
+	go func() {
+		defer r.millWg.Done()
+		r.mill()
+	}()
+	return nil
+}
+
+func (r *RotatingFile) backupName(t time.Time) string {
+	dir := filepath.Dir(r.cfg.Filename)
+	base := filepath.Base(r.cfg.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.Format(backupTimeFmt), ext))
+}
+
+// backupFile describes a rotated file found on disk.
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// backups lists rotated files belonging to this RotatingFile, newest first.
+func (r *RotatingFile) backups() ([]backupFile, error) {
+	dir := filepath.Dir(r.cfg.Filename)
+	base := filepath.Base(r.cfg.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []backupFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, compressSuffix)
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFmt, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{path: filepath.Join(dir, name), timestamp: t})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].timestamp.After(files[j].timestamp) })
+	return files, nil
+}
+
+// mill compresses rotated files and enforces MaxBackups/MaxAge.
+// Errors are reported to stderr because the logger cannot log about itself.
+func (r *RotatingFile) mill() {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	files, err := r.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logw: failed to list rotated files: %v\n", err)
+		return
+	}
+
+	var cutoff time.Time
+	if r.cfg.MaxAge > 0 {
+		cutoff = time.Now().Add(-time.Duration(r.cfg.MaxAge) * 24 * time.Hour)
+	}
+
+	for i, f := range files {
+		expired := !cutoff.IsZero() && f.timestamp.Before(cutoff)
+		if (r.cfg.MaxBackups > 0 && i >= r.cfg.MaxBackups) || expired {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "logw: failed to remove rotated file %s: %v\n", f.path, err)
+			}
+			continue
+		}
+		if r.cfg.Compress && !strings.HasSuffix(f.path, compressSuffix) {
+			if err := compressFile(f.path); err != nil {
+				fmt.Fprintf(os.Stderr, "logw: failed to compress rotated file %s: %v\n", f.path, err)
+			}
+		}
+	}
+}
+
+func compressFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	dst := src + compressSuffix
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		_ = gz.Close()
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// Close closes the active file and waits for pending compression/cleanup to finish.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+
+	r.millWg.Wait()
+	return err
+}
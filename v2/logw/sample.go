@@ -0,0 +1,258 @@
+package logw
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSampleInterval  = time.Second
+	defaultSummaryInterval = time.Minute
+)
+
+type templateKey struct{}
+
+// withTemplate records the printf template of a log call so samplers can group
+// "user 1 not found" and "user 2 not found" under the same key.
+func withTemplate(ctx context.Context, format string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, templateKey{}, format)
+}
+
+// SamplingConfig limits how many identical records are written on hot paths.
+// Records are keyed by level + message template (the printf format for the *f helpers).
+type SamplingConfig struct {
+	// Interval is the sampling window (default 1s).
+	Interval time.Duration
+	// First is the number of records logged per key and window before sampling kicks in.
+	First int
+	// Thereafter logs every Mth record after First within the window (0 drops them all).
+	Thereafter int
+
+	// RateLimit is the sustained number of records per second allowed per key (0 disables it).
+	RateLimit float64
+	// Burst is the number of records a key may emit at once before RateLimit applies (default 1).
+	Burst int
+
+	// SummaryInterval controls how often a summary of dropped records is logged (default 1m).
+	SummaryInterval time.Duration
+}
+
+// sampleKey groups records for sampling: level + message template.
+type sampleKey struct {
+	level    slog.Level
+	template string
+}
+
+// droppedRecords is a summary line: the message goes in a value, so the redact handler masks it.
+type droppedRecords struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Count   uint64 `json:"count"`
+}
+
+// tokenBucket is a minimal per-key rate limiter.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// sampler holds the state shared by a SamplingHandler and every handler derived from it.
+type sampler struct {
+	cfg  SamplingConfig
+	root slog.Handler // summaries bypass sampling and the attributes of derived handlers
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+	buckets     map[sampleKey]*tokenBucket
+	dropped     map[sampleKey]uint64
+	total       uint64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// SamplingHandler drops repetitive records according to a SamplingConfig and
+// periodically logs how many records were dropped per key.
+type SamplingHandler struct {
+	next slog.Handler
+	s    *sampler
+}
+
+// NewSamplingHandler wraps next with sampling and rate limiting, and starts the summary goroutine.
+// Call Close to stop it and emit the final summary.
+func NewSamplingHandler(next slog.Handler, cfg SamplingConfig) *SamplingHandler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultSampleInterval
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = 1
+	}
+	if cfg.SummaryInterval <= 0 {
+		cfg.SummaryInterval = defaultSummaryInterval
+	}
+
+	s := &sampler{
+		cfg:         cfg,
+		root:        next,
+		windowStart: time.Now(),
+		counts:      make(map[sampleKey]int),
+		buckets:     make(map[sampleKey]*tokenBucket),
+		dropped:     make(map[sampleKey]uint64),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.summaryLoop()
+
+	return &SamplingHandler{next: next, s: s}
+}
+
+func (s *sampler) allow(key sampleKey, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.windowStart) >= s.cfg.Interval {
+		s.windowStart = now
+		s.counts = make(map[sampleKey]int)
+	}
+
+	allowed := true
+	if s.cfg.First > 0 || s.cfg.Thereafter > 0 {
+		s.counts[key]++
+		if n := s.counts[key]; n > s.cfg.First {
+			allowed = s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0
+		}
+	}
+
+	if allowed && s.cfg.RateLimit > 0 {
+		b, ok := s.buckets[key]
+		if !ok {
+			b = &tokenBucket{tokens: float64(s.cfg.Burst), last: now}
+			s.buckets[key] = b
+		}
+		b.tokens += now.Sub(b.last).Seconds() * s.cfg.RateLimit
+		if b.tokens > float64(s.cfg.Burst) {
+			b.tokens = float64(s.cfg.Burst)
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+		} else {
+			allowed = false
+		}
+	}
+
+	if !allowed {
+		s.dropped[key]++
+		s.total++
+	}
+	return allowed
+}
+
+func (s *sampler) summaryLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.SummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.emitSummary()
+		case <-s.stop:
+			s.emitSummary()
+			return
+		}
+	}
+}
+
+// emitSummary logs and resets the per-key dropped counters.
+// Idle rate limiter buckets are pruned at the same time to bound memory.
+func (s *sampler) emitSummary() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = make(map[sampleKey]uint64)
+	now := time.Now()
+	for key, b := range s.buckets {
+		if now.Sub(b.last).Seconds()*s.cfg.RateLimit >= float64(s.cfg.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(dropped) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	if !s.root.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	var sum uint64
+	perKey := make([]droppedRecords, 0, len(dropped))
+	for key, n := range dropped {
+		sum += n
+		perKey = append(perKey, droppedRecords{Level: key.level.String(), Message: key.template, Count: n})
+	}
+	slices.SortFunc(perKey, func(a, b droppedRecords) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Message, b.Message))
+	})
+
+	rec := slog.NewRecord(now, slog.LevelWarn, "logw: dropped log records due to sampling", 0)
+	rec.AddAttrs(slog.Uint64("dropped_total", sum), slog.Any("dropped", perKey))
+	_ = s.root.Handle(ctx, rec)
+}
+
+// Dropped returns the total number of records dropped since the handler was created.
+func (h *SamplingHandler) Dropped() uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.total
+}
+
+// Close stops the summary goroutine after emitting a final summary.
+// Its signature matches gracefulw.CleanupFunc.
+func (h *SamplingHandler) Close(ctx context.Context) error {
+	h.s.stopOnce.Do(func() { close(h.s.stop) })
+	select {
+	case <-h.s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	template := r.Message
+	if ctx != nil {
+		if t, ok := ctx.Value(templateKey{}).(string); ok {
+			template = t
+		}
+	}
+
+	if !h.s.allow(sampleKey{level: r.Level, template: template}, time.Now()) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), s: h.s}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), s: h.s}
+}
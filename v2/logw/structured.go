@@ -0,0 +1,107 @@
+package logw
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logKV emits a record with alternating key-value pairs appended after the context attributes.
+func (l *Logger) logKV(ctx context.Context, level slog.Level, msg string, kv ...any) {
+	if !l.sl.Enabled(ctx, level) {
+		return
+	}
+	l.sl.Log(ctx, level, msg, append(BuildLogAttributes(ctx), kv...)...)
+}
+
+// logAttrs emits a record with typed attributes appended after the context attributes.
+func (l *Logger) logAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !l.sl.Enabled(ctx, level) {
+		return
+	}
+	l.sl.LogAttrs(ctx, level, msg, append(contextAttrs(ctx), attrs...)...)
+}
+
+// CtxDebugKV logs a message at Debug level with structured key-value pairs,
+// e.g. CtxDebugKV(ctx, "cache miss", "key", key, "shard", 3).
+func (l *Logger) CtxDebugKV(ctx context.Context, msg string, kv ...any) {
+	l.logKV(ctx, slog.LevelDebug, msg, kv...)
+}
+
+// CtxInfoKV logs a message at Info level with structured key-value pairs.
+func (l *Logger) CtxInfoKV(ctx context.Context, msg string, kv ...any) {
+	l.logKV(ctx, slog.LevelInfo, msg, kv...)
+}
+
+// CtxWarningKV logs a message at Warning level with structured key-value pairs.
+func (l *Logger) CtxWarningKV(ctx context.Context, msg string, kv ...any) {
+	l.logKV(ctx, slog.LevelWarn, msg, kv...)
+}
+
+// CtxErrorKV logs a message at Error level with structured key-value pairs.
+func (l *Logger) CtxErrorKV(ctx context.Context, msg string, kv ...any) {
+	l.logKV(ctx, slog.LevelError, msg, kv...)
+}
+
+// CtxDebugAttrs logs a message at Debug level with typed slog attributes.
+func (l *Logger) CtxDebugAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+// CtxInfoAttrs logs a message at Info level with typed slog attributes.
+func (l *Logger) CtxInfoAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, slog.LevelInfo, msg, attrs...)
+}
+
+// CtxWarningAttrs logs a message at Warning level with typed slog attributes.
+func (l *Logger) CtxWarningAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, slog.LevelWarn, msg, attrs...)
+}
+
+// CtxErrorAttrs logs a message at Error level with typed slog attributes.
+func (l *Logger) CtxErrorAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, slog.LevelError, msg, attrs...)
+}
+
+// CtxDebugKV logs structured key-value pairs at Debug level using the Logger stored in the context (or the default).
+func CtxDebugKV(ctx context.Context, msg string, kv ...any) {
+	FromContext(ctx).CtxDebugKV(ctx, msg, kv...)
+}
+
+// CtxInfoKV logs structured key-value pairs at Info level using the Logger stored in the context (or the default).
+//
+// Example:
+//
+//	logw.CtxInfoKV(ctx, "order created", "order_id", order.ID, "amount", order.Amount)
+func CtxInfoKV(ctx context.Context, msg string, kv ...any) {
+	FromContext(ctx).CtxInfoKV(ctx, msg, kv...)
+}
+
+// CtxWarningKV logs structured key-value pairs at Warning level using the Logger stored in the context (or the default).
+func CtxWarningKV(ctx context.Context, msg string, kv ...any) {
+	FromContext(ctx).CtxWarningKV(ctx, msg, kv...)
+}
+
+// CtxErrorKV logs structured key-value pairs at Error level using the Logger stored in the context (or the default).
+func CtxErrorKV(ctx context.Context, msg string, kv ...any) {
+	FromContext(ctx).CtxErrorKV(ctx, msg, kv...)
+}
+
+// CtxDebugAttrs logs typed attributes at Debug level using the Logger stored in the context (or the default).
+func CtxDebugAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	FromContext(ctx).CtxDebugAttrs(ctx, msg, attrs...)
+}
+
+// CtxInfoAttrs logs typed attributes at Info level using the Logger stored in the context (or the default).
+func CtxInfoAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	FromContext(ctx).CtxInfoAttrs(ctx, msg, attrs...)
+}
+
+// CtxWarningAttrs logs typed attributes at Warning level using the Logger stored in the context (or the default).
+func CtxWarningAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	FromContext(ctx).CtxWarningAttrs(ctx, msg, attrs...)
+}
+
+// CtxErrorAttrs logs typed attributes at Error level using the Logger stored in the context (or the default).
+func CtxErrorAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	FromContext(ctx).CtxErrorAttrs(ctx, msg, attrs...)
+}
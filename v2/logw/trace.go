@@ -0,0 +1,80 @@
+package logw
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+const traceContextKey contextKey = "logw-trace-context"
+
+// traceContext is the W3C trace context (https://www.w3.org/TR/trace-context/) carried by a request.
+type traceContext struct {
+	traceID string
+	spanID  string
+}
+
+// isHexID reports whether s is a non-zero lowercase hex identifier of the given byte length.
+func isHexID(s string, size int) bool {
+	if len(s) != size*2 || strings.Trim(s, "0") == "" {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
+
+// WithTraceContext returns a copy of ctx carrying a W3C trace ID (32 hex chars) and span ID (16 hex chars).
+// Invalid identifiers are ignored and ctx is returned unmodified.
+func WithTraceContext(ctx context.Context, traceID, spanID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	traceID, spanID = strings.ToLower(traceID), strings.ToLower(spanID)
+	if !isHexID(traceID, 16) {
+		return ctx
+	}
+	if !isHexID(spanID, 8) {
+		spanID = ""
+	}
+	return context.WithValue(ctx, traceContextKey, traceContext{traceID: traceID, spanID: spanID})
+}
+
+// isHexByte reports whether s is a single byte encoded as two lowercase hex characters.
+func isHexByte(s string) bool {
+	_, err := hex.DecodeString(s)
+	return len(s) == 2 && err == nil && strings.ToLower(s) == s
+}
+
+// ContextFromTraceparent parses a W3C "traceparent" header (e.g. "00-<trace-id>-<span-id>-01")
+// and stores it in the context. Malformed headers are ignored. Version 00 headers must have exactly
+// four fields; later versions may append fields, which are ignored.
+func ContextFromTraceparent(ctx context.Context, header string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || !isHexByte(parts[0]) || parts[0] == "ff" || !isHexByte(parts[3]) {
+		return ctx
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return ctx
+	}
+	return WithTraceContext(ctx, parts[1], parts[2])
+}
+
+// GetTraceContext returns the trace and span IDs used to correlate log entries.
+// When no W3C trace context exists, the trace ID is derived from the x-log-id (a UUID is
+// exactly 16 bytes), so older services that only propagate x-log-id still correlate.
+// Both values are empty if neither is available.
+func GetTraceContext(ctx context.Context) (traceID, spanID string) {
+	if ctx == nil {
+		return "", ""
+	}
+	if tc, ok := ctx.Value(traceContextKey).(traceContext); ok {
+		return tc.traceID, tc.spanID
+	}
+	if id := strings.ToLower(strings.ReplaceAll(GetLogID(ctx), "-", "")); isHexID(id, 16) {
+		return id, ""
+	}
+	return "", ""
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
+const loggerName = "elasticw"
+
 type contextKey string
 
 const debugKey contextKey = "elasticw-debug"
@@ -58,7 +60,7 @@ func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	// If network error occurred
 	if err != nil {
-		logw.CtxErrorf(ctx, "[ELASTIC FAILED] %s %s | Duration: %v | err: %v", req.Method, req.URL.Path, duration, err)
+		logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "[ELASTIC FAILED] %s %s | Duration: %v | err: %v", req.Method, req.URL.Path, duration, err)
 		return res, err
 	}
 
@@ -87,9 +89,9 @@ func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	// Use Warning level for 4xx and 5xx status codes
 	if res.StatusCode >= 400 {
-		logw.CtxWarning(ctx, logMsg)
+		logw.FromContext(ctx).Named(loggerName).CtxWarning(ctx, logMsg)
 	} else {
-		logw.CtxInfo(ctx, logMsg)
+		logw.FromContext(ctx).Named(loggerName).CtxInfo(ctx, logMsg)
 	}
 
 	return res, nil
@@ -134,7 +136,7 @@ func Connect(ctx context.Context, cfg *Config) (*elasticsearch.TypedClient, erro
 		return nil, fmt.Errorf("elasticw: failed to ping elasticsearch cluster: %w", err)
 	}
 
-	logw.CtxInfof(ctx, "Successfully connected to Elasticsearch")
+	logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "Successfully connected to Elasticsearch")
 	return client, nil
 }
 
@@ -147,7 +149,7 @@ func Disconnect(client *elasticsearch.TypedClient) func(ctx context.Context) err
 		if client != nil {
 			// Kita hanya perlu mencetak log, karena koneksi HTTP Elasticsearch
 			// akan otomatis diurus oleh garbage collector dan IdleTimeout bawaan Golang.
-			logw.CtxInfo(ctx, "Detaching Elasticsearch client...")
+			logw.FromContext(ctx).Named(loggerName).CtxInfo(ctx, "Detaching Elasticsearch client...")
 		}
 		return nil
 	}
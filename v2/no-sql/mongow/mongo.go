@@ -14,6 +14,8 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+const loggerName = "mongow"
+
 type contextKey string
 
 const debugKey contextKey = "mongow-debug"
@@ -40,7 +42,7 @@ func buildCommandMonitor(globalDebug bool) *event.CommandMonitor {
 			isDebug, _ := ctx.Value(debugKey).(bool)
 			if globalDebug || isDebug {
 				// In v2, evt.Command is a bson.Raw; .String() provides a readable version.
-				logw.CtxInfof(ctx, "[MONGO DEBUG] DB: %s | Cmd: %s | Query: %s",
+				logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[MONGO DEBUG] DB: %s | Cmd: %s | Query: %s",
 					evt.DatabaseName, evt.CommandName, evt.Command.String())
 			}
 		},
@@ -48,14 +50,14 @@ func buildCommandMonitor(globalDebug bool) *event.CommandMonitor {
 			isDebug, _ := ctx.Value(debugKey).(bool)
 			if globalDebug || isDebug {
 				duration := evt.Duration
-				logw.CtxInfof(ctx, "[MONGO SUCCESS] Cmd: %s | Duration: %v", evt.CommandName, duration)
+				logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[MONGO SUCCESS] Cmd: %s | Duration: %v", evt.CommandName, duration)
 			}
 		},
 		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
 			isDebug, _ := ctx.Value(debugKey).(bool)
 			if globalDebug || isDebug {
 				duration := evt.Duration
-				logw.CtxErrorf(ctx, "[MONGO FAILED] Cmd: %s | Duration: %v | err: %v",
+				logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "[MONGO FAILED] Cmd: %s | Duration: %v | err: %v",
 					evt.CommandName, duration, evt.Failure)
 			}
 		},
@@ -105,7 +107,7 @@ func Connect(ctx context.Context, cfg *Config) (*mongo.Client, error) {
 		return nil, fmt.Errorf("mongow: failed to ping mongo server: %w", err)
 	}
 
-	logw.CtxInfof(ctx, "Successfully connected to MongoDB (%s)", cfg.URI)
+	logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "Successfully connected to MongoDB (%s)", cfg.URI)
 	return client, nil
 }
 
@@ -114,7 +116,7 @@ func Connect(ctx context.Context, cfg *Config) (*mongo.Client, error) {
 func Disconnect(client *mongo.Client) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
 		if client != nil {
-			logw.CtxInfo(ctx, "Closing MongoDB connection...")
+			logw.FromContext(ctx).Named(loggerName).CtxInfo(ctx, "Closing MongoDB connection...")
 			return client.Disconnect(ctx)
 		}
 		return nil
@@ -9,6 +9,8 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+const loggerName = "redisw"
+
 type contextKey string
 
 const debugKey contextKey = "redisw-debug"
@@ -47,9 +49,9 @@ func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 			duration := time.Since(start)
 
 			if err != nil && err != redis.Nil {
-				logw.CtxErrorf(ctx, "[REDIS DEBUG] %v | Cmd: %s | err: %v", duration, cmd.String(), err)
+				logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "[REDIS DEBUG] %v | Cmd: %s | err: %v", duration, cmd.String(), err)
 			} else {
-				logw.CtxInfof(ctx, "[REDIS DEBUG] %v | Cmd: %s", duration, cmd.String())
+				logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[REDIS DEBUG] %v | Cmd: %s", duration, cmd.String())
 			}
 			return err
 		}
@@ -79,9 +81,9 @@ func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.Pr
 			cmdStr += "]"
 
 			if err != nil && err != redis.Nil {
-				logw.CtxErrorf(ctx, "[REDIS DEBUG PIPELINE] %v | Cmds: %s | err: %v", duration, cmdStr, err)
+				logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "[REDIS DEBUG PIPELINE] %v | Cmds: %s | err: %v", duration, cmdStr, err)
 			} else {
-				logw.CtxInfof(ctx, "[REDIS DEBUG PIPELINE] %v | Cmds: %s", duration, cmdStr)
+				logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[REDIS DEBUG PIPELINE] %v | Cmds: %s", duration, cmdStr)
 			}
 			return err
 		}
@@ -118,7 +120,7 @@ func Connect(ctx context.Context, cfg *Config) (*redis.Client, error) {
 		return nil, fmt.Errorf("redisw: failed to connect to redis at %s: %w", cfg.Address, err)
 	}
 
-	logw.CtxInfof(ctx, "Successfully connected to Redis (%s)", cfg.Address)
+	logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "Successfully connected to Redis (%s)", cfg.Address)
 	return client, nil
 }
 
@@ -127,7 +129,7 @@ func Connect(ctx context.Context, cfg *Config) (*redis.Client, error) {
 func Disconnect(client *redis.Client) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
 		if client != nil {
-			logw.CtxInfo(ctx, "Closing Redis connection...")
+			logw.FromContext(ctx).Named(loggerName).CtxInfo(ctx, "Closing Redis connection...")
 			return client.Close()
 		}
 		return nil
@@ -13,6 +13,8 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+const loggerName = "gormw"
+
 type contextKey string
 
 const txKey contextKey = "gormw-tx"
@@ -50,19 +52,19 @@ func (l *customLogger) LogMode(level logger.LogLevel) logger.Interface {
 
 func (l *customLogger) Info(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Info {
-		logw.CtxInfof(ctx, msg, data...)
+		logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, msg, data...)
 	}
 }
 
 func (l *customLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Warn {
-		logw.CtxWarningf(ctx, msg, data...)
+		logw.FromContext(ctx).Named(loggerName).CtxWarningf(ctx, msg, data...)
 	}
 }
 
 func (l *customLogger) Error(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Error {
-		logw.CtxErrorf(ctx, msg, data...)
+		logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, msg, data...)
 	}
 }
 
@@ -74,9 +76,9 @@ func (l *customLogger) Trace(ctx context.Context, begin time.Time, fc func() (sq
 	sql, rows := fc() // GORM automatically interpolates parameters here!
 
 	if err != nil {
-		logw.CtxErrorf(ctx, "[GORM] %v | %s | rows: %d | err: %v", elapsed, sql, rows, err)
+		logw.FromContext(ctx).Named(loggerName).CtxErrorf(ctx, "[GORM] %v | %s | rows: %d | err: %v", elapsed, sql, rows, err)
 	} else if l.LogLevel >= logger.Info {
-		logw.CtxInfof(ctx, "[GORM] %v | %s | rows: %d", elapsed, sql, rows)
+		logw.FromContext(ctx).Named(loggerName).CtxInfof(ctx, "[GORM] %v | %s | rows: %d", elapsed, sql, rows)
 	}
 }
 
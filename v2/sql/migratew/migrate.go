@@ -19,6 +19,8 @@ import (
 	"github.com/AndreeJait/go-utility/v2/logw" // Adjust to your actual module path
 )
 
+const loggerName = "migratew"
+
 // DriverName represents the supported SQL database drivers.
 type DriverName string
 
@@ -120,21 +122,21 @@ func New(db *sql.DB, driver DriverName, migrationFS fs.FS, dirPath string, opts
 }
 
 func (m *migrator) Up() error {
-	logw.Info("migratew: running Up migrations...")
+	logw.Named(loggerName).Info("migratew: running Up migrations...")
 	err := m.engine.Up()
 	if err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
-			logw.Info("migratew: database is already up to date. No changes applied.")
+			logw.Named(loggerName).Info("migratew: database is already up to date. No changes applied.")
 			return nil
 		}
 		return fmt.Errorf("migratew: failed to apply up migrations: %w", err)
 	}
-	logw.Info("migratew: successfully applied all up migrations.")
+	logw.Named(loggerName).Info("migratew: successfully applied all up migrations.")
 	return nil
 }
 
 func (m *migrator) Down() error {
-	logw.Warning("migratew: running Down migrations (rolling back all!)...")
+	logw.Named(loggerName).Warning("migratew: running Down migrations (rolling back all!)...")
 	err := m.engine.Down()
 	if err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
@@ -142,12 +144,12 @@ func (m *migrator) Down() error {
 		}
 		return fmt.Errorf("migratew: failed to apply down migrations: %w", err)
 	}
-	logw.Info("migratew: successfully rolled back all migrations.")
+	logw.Named(loggerName).Info("migratew: successfully rolled back all migrations.")
 	return nil
 }
 
 func (m *migrator) Steps(n int) error {
-	logw.Infof("migratew: running migrations by %d steps...", n)
+	logw.Named(loggerName).Infof("migratew: running migrations by %d steps...", n)
 	err := m.engine.Steps(n)
 	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return fmt.Errorf("migratew: failed to execute steps: %w", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/AndreeJait/go-utility/v2/logw"
@@ -14,6 +15,8 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+const loggerName = "sqlxw"
+
 type contextKey string
 
 const txKey contextKey = "sqlxw-tx"
@@ -49,9 +52,10 @@ type debugWrapper struct {
 	ExtContext
 }
 
-// logQuery prints the SQL query and its appended parameters.
+// logQuery prints the SQL query and its appended parameters. They are attributes rather than part of
+// the message, so logw's redaction applies to them.
 func logQuery(ctx context.Context, method, query string, args []interface{}) {
-	logw.CtxInfof(ctx, "[SQLX DEBUG] %s | Query: %s | Args: %v", method, query, args)
+	logw.FromContext(ctx).Named(loggerName).CtxInfoKV(ctx, "[SQLX DEBUG] "+method, "query", query, "args", args)
 }
 
 func (d *debugWrapper) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
@@ -127,7 +131,8 @@ func Transaction(ctx context.Context, db *sqlx.DB, fn func(txCtx context.Context
 }
 
 // GetDB extracts the SQLX transaction from the context if it exists.
-// It applies a debug wrapper automatically if the global isDebug flag is true.
+// It applies a debug wrapper automatically if the global isDebug flag is true
+// or the "sqlxw" logger is set to debug (e.g. logw.SetLevelFor("sqlxw", slog.LevelDebug)).
 func GetDB(ctx context.Context, defaultDB *sqlx.DB, isDebug bool) ExtContext {
 	var db ExtContext
 	if tx, ok := ctx.Value(txKey).(*sqlx.Tx); ok {
@@ -136,7 +141,7 @@ func GetDB(ctx context.Context, defaultDB *sqlx.DB, isDebug bool) ExtContext {
 		db = defaultDB
 	}
 
-	if isDebug {
+	if isDebug || logw.FromContext(ctx).Named(loggerName).Slog().Enabled(ctx, slog.LevelDebug) {
 		return &debugWrapper{ExtContext: db}
 	}
 	return db
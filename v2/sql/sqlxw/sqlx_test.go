@@ -1,10 +1,14 @@
 package sqlxw
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 
+	"github.com/AndreeJait/go-utility/v2/logw"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -38,7 +42,7 @@ func TestSqlxTransaction_Commit(t *testing.T) {
 	ctx := context.Background()
 
 	err := Transaction(ctx, db, func(txCtx context.Context) error {
-		repoDB := GetDB(txCtx, db)
+		repoDB := GetDB(txCtx, db, false)
 		_, err := repoDB.ExecContext(txCtx, "INSERT INTO users (id, name) VALUES (?, ?)", 1, "Alice")
 		return err
 	})
@@ -61,7 +65,7 @@ func TestSqlxTransaction_Rollback(t *testing.T) {
 	ctx := context.Background()
 
 	_ = Transaction(ctx, db, func(txCtx context.Context) error {
-		repoDB := GetDB(txCtx, db)
+		repoDB := GetDB(txCtx, db, false)
 		_, _ = repoDB.ExecContext(txCtx, "INSERT INTO users (id, name) VALUES (?, ?)", 2, "Bob")
 		return errors.New("simulated error")
 	})
@@ -72,3 +76,54 @@ func TestSqlxTransaction_Rollback(t *testing.T) {
 		t.Errorf("Expected 0 records after rollback, got %d", count)
 	}
 }
+
+func TestGetDB_LevelOverride(t *testing.T) {
+	db := setupTestDB(t)
+	defer Disconnect(db)(context.Background())
+
+	var buf bytes.Buffer
+	l, err := logw.NewLogger(&logw.LogConfig{Level: "info", SendToBroker: true, BrokerWriter: &buf})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	ctx := logw.NewContext(context.Background(), l)
+
+	_, _ = GetDB(ctx, db, false).ExecContext(ctx, "INSERT INTO users (id, name) VALUES (?, ?)", 3, "Carol")
+	if buf.Len() != 0 {
+		t.Errorf("Expected no query logs at info level, got %s", buf.String())
+	}
+
+	l.Levels().SetOverride(loggerName, slog.LevelDebug)
+	_, _ = GetDB(ctx, db, false).ExecContext(ctx, "INSERT INTO users (id, name) VALUES (?, ?)", 4, "Dave")
+	if !strings.Contains(buf.String(), "Dave") || !strings.Contains(buf.String(), `"logger":"sqlxw"`) {
+		t.Errorf("Expected the sqlxw=debug override to log the query, got %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Levels().SetOverride(loggerName, slog.LevelError)
+	_, _ = GetDB(ctx, db, true).ExecContext(ctx, "INSERT INTO users (id, name) VALUES (?, ?)", 5, "Erin")
+	if buf.Len() != 0 {
+		t.Errorf("Expected the sqlxw=error override to silence DebugMode queries, got %s", buf.String())
+	}
+}
+
+func TestDebug_RedactsArgs(t *testing.T) {
+	db := setupTestDB(t)
+	defer Disconnect(db)(context.Background())
+
+	var buf bytes.Buffer
+	l, err := logw.NewLogger(&logw.LogConfig{
+		SendToBroker: true,
+		BrokerWriter: &buf,
+		Redact:       &logw.RedactConfig{Patterns: logw.DefaultRedactPatterns},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	ctx := logw.NewContext(context.Background(), l)
+
+	_, _ = Debug(db).ExecContext(ctx, "INSERT INTO users (id, name) VALUES (?, ?)", 6, "frank@example.com")
+	if strings.Contains(buf.String(), "frank@example.com") || !strings.Contains(buf.String(), `"args":[6,"[REDACTED]"]`) {
+		t.Errorf("Expected the query args to be logged as a redacted attribute, got %s", buf.String())
+	}
+}